@@ -0,0 +1,112 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// BaselineVersion is the version of the baseline file format.
+const BaselineVersion = 1
+
+// BaselineEntry is a known diagnostic stored in a baseline.
+type BaselineEntry struct {
+	// Fingerprint is the stable identity of the diagnostic.
+	Fingerprint string `json:"fingerprint"`
+	// Location is the location of the diagnostic, for the reviewers only.
+	Location string `json:"location,omitempty"`
+	// Type is the type of the diagnostic, for the reviewers only.
+	Type string `json:"type,omitempty"`
+	// Message is the message of the diagnostic, for the reviewers only.
+	Message string `json:"message,omitempty"`
+}
+
+// Baseline is a set of known diagnostics.
+// Only the diagnostics which are not in the baseline are reported by Filter.
+// The diagnostics are matched by the fingerprints of the traces computed without the line and the column,
+// so the lines can shift, i.e. the fingerprints set by WithFingerprints(WithoutFingerprintPosition(), opts...).
+// The locations are made relative with WithFingerprintRoot, so the baseline can be moved between checkouts.
+// The same fingerprint options must be used to create and to filter with a baseline.
+type Baseline struct {
+	Version int             `json:"version"`
+	Entries []BaselineEntry `json:"diagnostics"`
+}
+
+// baselineFingerprintOptions returns the fingerprint options of the baselines, the position is excluded by default.
+func baselineFingerprintOptions(opts []FingerprintOpt) *FingerprintOptions {
+	o := NewFingerprintOptions()
+	o.ExcludePosition = true
+	for _, opt := range opts {
+		opt.Apply(o)
+	}
+	return o
+}
+
+// NewBaseline creates a new baseline from the given traces.
+func NewBaseline(traces []Trace, opts ...FingerprintOpt) *Baseline {
+	o := baselineFingerprintOptions(opts)
+	b := &Baseline{
+		Version: BaselineVersion,
+		Entries: make([]BaselineEntry, 0, len(traces)),
+	}
+	for i := range traces {
+		trace := &traces[i]
+		if len(trace.Stack) == 0 {
+			continue
+		}
+		stack := trace.Stack[len(trace.Stack)-1]
+		b.Entries = append(b.Entries, BaselineEntry{
+			Fingerprint: traceFingerprint(trace, o),
+			Location:    o.location(stack.Location),
+			Type:        stack.Type.String(),
+			Message:     stack.Message,
+		})
+	}
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		if b.Entries[i].Location != b.Entries[j].Location {
+			return b.Entries[i].Location < b.Entries[j].Location
+		}
+		return b.Entries[i].Fingerprint < b.Entries[j].Fingerprint
+	})
+	return b
+}
+
+// ReadBaseline reads the JSON baseline.
+func ReadBaseline(r io.Reader) (*Baseline, error) {
+	b := &Baseline{}
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, fmt.Errorf("decode baseline: %w", err)
+	}
+	if b.Version != BaselineVersion {
+		return nil, fmt.Errorf("decode baseline: unsupported version %d", b.Version)
+	}
+	return b, nil
+}
+
+// Write writes the baseline as JSON.
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Filter returns the traces which are not in the baseline.
+// Every baseline entry matches one trace, so a diagnostic occurring more often than in the baseline is reported.
+func (b *Baseline) Filter(traces []Trace, opts ...FingerprintOpt) []Trace {
+	o := baselineFingerprintOptions(opts)
+	known := make(map[string]int, len(b.Entries))
+	for _, entry := range b.Entries {
+		known[entry.Fingerprint]++
+	}
+	result := make([]Trace, 0)
+	for i := range traces {
+		fp := traceFingerprint(&traces[i], o)
+		if known[fp] > 0 {
+			known[fp]--
+			continue
+		}
+		result = append(result, traces[i])
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package stacktrace
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBaseline_Filter(t *testing.T) {
+	old := New("validation failed").
+		Append(New("property  baseUri is not allowed", WithType("unknown-property"), WithLocation("api.raml"), WithPosition(NewPosition(3, 1)))).
+		Append(New("expected 2 items", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(10, 1)))).
+		Append(New("expected 2 items", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(12, 1))))
+
+	var buf bytes.Buffer
+	if err := NewBaseline(old.GetTraces()).Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	baseline, err := ReadBaseline(&buf)
+	if err != nil {
+		t.Fatalf("ReadBaseline() error = %v", err)
+	}
+
+	current := New("validation failed").
+		Append(New("property baseUri is not allowed", WithType("unknown-property"), WithLocation("api.raml"), WithPosition(NewPosition(5, 3)))).
+		Append(New("expected 3 items", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(11, 1)))).
+		Append(New("expected 2 items", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(13, 1)))).
+		Append(New("expected 2 items", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(20, 1)))).
+		Append(New("property baseUri is not allowed", WithType("unknown-property"), WithLocation("other.raml"), WithPosition(NewPosition(3, 1))))
+
+	got := baseline.Filter(current.GetTraces())
+	// the counts in the messages are kept, so "expected 3 items" is a new diagnostic
+	want := []string{"api.raml:11:1", "other.raml:3:1"}
+	if len(got) != len(want) {
+		t.Fatalf("Filter() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if *got[i].Stack[0].LinePos != want[i] {
+			t.Errorf("Filter()[%d] = %v, want %v", i, *got[i].Stack[0].LinePos, want[i])
+		}
+	}
+}
+
+func TestBaseline_FilterLocalizedAndMoved(t *testing.T) {
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{"file not found": "Datei nicht gefunden"}}
+	report := func(root string) *StackTrace {
+		return New("validation failed").
+			Append(New("file not found", WithType("reading"), WithLocation(root+"/specs/api.raml"), WithPosition(NewPosition(3, 1))))
+	}
+
+	baseline := NewBaseline(report("/home/ci").GetTraces(WithLocalizer(bundle)), WithFingerprintRoot("/home/ci"))
+	if got := baseline.Entries[0].Location; got != "specs/api.raml" {
+		t.Errorf("NewBaseline() location = %v, want specs/api.raml", got)
+	}
+	if got := baseline.Filter(report("/builds/42").GetTraces(), WithFingerprintRoot("/builds/42")); len(got) != 0 {
+		t.Errorf("Filter() = %v, want no new diagnostics", got)
+	}
+}
+
+func TestBaseline_FilterWithFingerprints(t *testing.T) {
+	report := func(line int) *StackTrace {
+		return New("validation failed").
+			Append(New("reading failed", WithType("reading"), WithLocation("api.raml"), WithPosition(NewPosition(1, 1))).
+				Wrap(New("file not found", WithType("reading"), WithLocation("types.raml"), WithPosition(NewPosition(line, 1)))))
+	}
+
+	for _, opts := range [][]FingerprintOpt{nil, {WithFingerprintWrapPath()}} {
+		baseline := NewBaseline(report(3).GetTraces(WithFingerprints()), opts...)
+		if got := baseline.Filter(report(7).GetTraces(WithFingerprints()), opts...); len(got) != 0 {
+			t.Errorf("Filter() = %v, want no new diagnostics", got)
+		}
+		if got := baseline.Filter(New("validation failed").Append(New("file not found", WithType("reading"),
+			WithLocation("other.raml"))).GetTraces(WithFingerprints()), opts...); len(got) != 1 {
+			t.Errorf("Filter() = %v, want a new diagnostic", got)
+		}
+	}
+}
+
+func TestNewBaseline_TraceFingerprints(t *testing.T) {
+	st := New("validation failed").
+		Append(New("reading failed", WithType("reading"), WithLocation("api.raml"), WithPosition(NewPosition(1, 1))).
+			Wrap(New("file not found", WithType("reading"), WithLocation("types.raml"), WithPosition(NewPosition(3, 1)))))
+
+	for _, opts := range [][]FingerprintOpt{nil, {WithFingerprintWrapPath()}} {
+		traces := st.GetTraces(WithFingerprints(append([]FingerprintOpt{WithoutFingerprintPosition()}, opts...)...))
+		baseline := NewBaseline(traces, opts...)
+		if len(baseline.Entries) != 1 || baseline.Entries[0].Fingerprint != traces[0].Fingerprint {
+			t.Errorf("NewBaseline() = %v, want the fingerprint %v", baseline.Entries, traces[0].Fingerprint)
+		}
+	}
+}
+
+func TestReadBaseline(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "Check valid",
+			data: `{"version": 1, "diagnostics": [{"fingerprint": "abc"}]}`,
+		},
+		{
+			name:    "Check unsupported version",
+			data:    `{"version": 2, "diagnostics": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "Check invalid json",
+			data:    `{`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadBaseline(strings.NewReader(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("ReadBaseline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{msg: "  expected  12 items\tat line 3 ", want: "expected 12 items at line 0"},
+		{msg: "unexpected token at 3:14", want: "unexpected token at 0"},
+		{msg: "request 6f1c2d3e-0a1b-4c5d-8e9f-001122334455 failed after 1700000000", want: "request 0 failed after 0"},
+		{msg: "v1.20", want: "v1.20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := normalizeMessage(tt.msg); got != tt.want {
+				t.Errorf("normalizeMessage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
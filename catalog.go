@@ -0,0 +1,145 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+// CatalogEntry is the documentation of an error code.
+type CatalogEntry struct {
+	// Code is the machine-stable code of the error.
+	Code Code `json:"code"`
+	// Title is the short human-readable summary of the error.
+	Title string `json:"title,omitempty"`
+	// Type is the type inherited by errors created with the code.
+	Type Type `json:"type,omitempty"`
+	// Severity is the default severity inherited by errors created with the code.
+	Severity Severity `json:"severity,omitempty"`
+	// Description is the long description of the error.
+	Description string `json:"description,omitempty"`
+	// Examples are the snippets illustrating the error.
+	Examples []string `json:"examples,omitempty"`
+	// HelpURL is the link to the documentation of the error.
+	HelpURL string `json:"helpUrl,omitempty"`
+
+	// Name is the Go name of the error used by the code generator, e.g. "UnknownProperty".
+	Name string `json:"name,omitempty"`
+	// Message is the message template of the error with named parameters, e.g. "property {name} is not allowed".
+	Message string `json:"message,omitempty"`
+	// Params maps the message parameters to their Go types. Parameters without a type are strings.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Catalog is a registry of error codes.
+// It is safe for concurrent use. The nil catalog has no entries and ignores the registrations.
+type Catalog struct {
+	mu      sync.RWMutex
+	entries map[Code]CatalogEntry
+}
+
+// DefaultCatalog is the catalog used by WithCode.
+var DefaultCatalog = NewCatalog()
+
+// NewCatalog creates a new empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		entries: make(map[Code]CatalogEntry),
+	}
+}
+
+// Register adds the given entries to the catalog, replacing the entries with the same code.
+func (c *Catalog) Register(entries ...CatalogEntry) *Catalog {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[Code]CatalogEntry)
+	}
+	for _, entry := range entries {
+		c.entries[entry.Code] = entry
+	}
+	return c
+}
+
+// LoadJSON registers the entries of the given JSON array of catalog entries.
+func (c *Catalog) LoadJSON(data []byte) error {
+	var entries []CatalogEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("decode catalog: %w", err)
+	}
+	for i, entry := range entries {
+		if entry.Code == "" {
+			return fmt.Errorf("decode catalog: entry %d: code is empty", i)
+		}
+	}
+	c.Register(entries...)
+	return nil
+}
+
+// LoadFS registers the entries of the JSON catalog file with the given name from fsys.
+func (c *Catalog) LoadFS(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("read catalog: %w", err)
+	}
+	if err := c.LoadJSON(data); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// Lookup returns the entry of the given code.
+func (c *Catalog) Lookup(code Code) (CatalogEntry, bool) {
+	if c == nil {
+		return CatalogEntry{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[code]
+	return entry, ok
+}
+
+// Entries returns the entries of the catalog sorted by code.
+func (c *Catalog) Entries() []CatalogEntry {
+	if c == nil {
+		return nil
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make([]CatalogEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// WithCode sets the code of the error and inherits the severity and the type from the catalog entry.
+func (c *Catalog) WithCode(code Code) Option {
+	return optErrCode{Code: code, Catalog: c}
+}
+
+// Register adds the given entries to the DefaultCatalog.
+func Register(entries ...CatalogEntry) {
+	DefaultCatalog.Register(entries...)
+}
+
+// Lookup returns the entry of the given code from the DefaultCatalog.
+func Lookup(code Code) (CatalogEntry, bool) {
+	return DefaultCatalog.Lookup(code)
+}
+
+// CatalogEntry returns the catalog entry of the StackTrace code from the DefaultCatalog.
+func (st *StackTrace) CatalogEntry() (CatalogEntry, bool) {
+	if st.Code == nil {
+		return CatalogEntry{}, false
+	}
+	return DefaultCatalog.Lookup(*st.Code)
+}
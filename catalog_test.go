@@ -0,0 +1,217 @@
+package stacktrace
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCatalog_WithCode(t *testing.T) {
+	var SeverityError Severity = "error"
+	var SeverityWarning Severity = "warning"
+	var TypeParsing Type = "parsing"
+	var TypeValidating Type = "validating"
+	var CodeUnknown Code = "RAML1023"
+	var CodeMissing Code = "RAML9999"
+
+	catalog := NewCatalog().Register(CatalogEntry{
+		Code:     CodeUnknown,
+		Title:    "Unknown property",
+		Type:     TypeValidating,
+		Severity: SeverityWarning,
+	})
+
+	type args struct {
+		opts []Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want *StackTrace
+	}{
+		{
+			name: "Check inherit severity and type",
+			args: args{
+				opts: []Option{catalog.WithCode(CodeUnknown)},
+			},
+			want: &StackTrace{
+				Message:   "message",
+				Code:      &CodeUnknown,
+				Severity:  &SeverityWarning,
+				Type:      &TypeValidating,
+				typeIsSet: true,
+			},
+		},
+		{
+			name: "Check explicit severity and type are kept",
+			args: args{
+				opts: []Option{WithSeverity(SeverityError), WithType(TypeParsing), catalog.WithCode(CodeUnknown)},
+			},
+			want: &StackTrace{
+				Message:   "message",
+				Code:      &CodeUnknown,
+				Severity:  &SeverityError,
+				Type:      &TypeParsing,
+				typeIsSet: true,
+			},
+		},
+		{
+			name: "Check unregistered code",
+			args: args{
+				opts: []Option{catalog.WithCode(CodeMissing)},
+			},
+			want: &StackTrace{
+				Message: "message",
+				Code:    &CodeMissing,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New("message", tt.args.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_Entries(t *testing.T) {
+	catalog := NewCatalog().Register(
+		CatalogEntry{Code: "B2", Title: "second"},
+		CatalogEntry{Code: "A1", Title: "first"},
+		CatalogEntry{Code: "B2", Title: "replaced"},
+	)
+	want := []CatalogEntry{
+		{Code: "A1", Title: "first"},
+		{Code: "B2", Title: "replaced"},
+	}
+	if got := catalog.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Entries() = %v, want %v", got, want)
+	}
+}
+
+func TestCatalog_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"errors/catalog.json": {Data: []byte(`[{"code": "RAML1023", "title": "Unknown property"}]`)},
+		"errors/invalid.json": {Data: []byte(`{}`)},
+	}
+	tests := []struct {
+		name    string
+		file    string
+		want    []CatalogEntry
+		wantErr string
+	}{
+		{
+			name: "Check load",
+			file: "errors/catalog.json",
+			want: []CatalogEntry{{Code: "RAML1023", Title: "Unknown property"}},
+		},
+		{
+			name:    "Check missing file",
+			file:    "errors/missing.json",
+			wantErr: "read catalog: open errors/missing.json: file does not exist",
+		},
+		{
+			name:    "Check invalid file",
+			file:    "errors/invalid.json",
+			wantErr: "errors/invalid.json: decode catalog: json: cannot unmarshal object into Go value of type []stacktrace.CatalogEntry",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := NewCatalog()
+			err := catalog.LoadFS(fsys, tt.file)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("LoadFS() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFS() error = %v", err)
+			}
+			if got := catalog.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Entries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_Nil(t *testing.T) {
+	var catalog *Catalog
+	if got := catalog.Register(CatalogEntry{Code: "A1"}); got != nil {
+		t.Errorf("Register() = %v, want nil", got)
+	}
+	if _, ok := catalog.Lookup("A1"); ok {
+		t.Errorf("Lookup() ok = true, want false")
+	}
+	if got := catalog.Entries(); got != nil {
+		t.Errorf("Entries() = %v, want nil", got)
+	}
+}
+
+func TestStackTrace_HeaderWithCode(t *testing.T) {
+	tests := []struct {
+		name string
+		st   *StackTrace
+		want string
+	}{
+		{
+			name: "Check type and code",
+			st:   New("message", WithType("parsing"), WithCode("RAML1023"), WithLocation("/tmp/api.raml")),
+			want: "parsing[RAML1023]: /tmp/api.raml:1: message",
+		},
+		{
+			name: "Check code only",
+			st:   New("message", WithCode("RAML1023")),
+			want: "[RAML1023]: message",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.st.Error(); got != tt.want {
+				t.Errorf("Error() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCatalog_LoadJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []CatalogEntry
+		wantErr bool
+	}{
+		{
+			name: "Check load",
+			data: `[{"code": "RAML1023", "title": "Unknown property", "severity": "warning", "examples": ["a: b"]}]`,
+			want: []CatalogEntry{
+				{Code: "RAML1023", Title: "Unknown property", Severity: "warning", Examples: []string{"a: b"}},
+			},
+		},
+		{
+			name:    "Check empty code",
+			data:    `[{"title": "Unknown property"}]`,
+			want:    []CatalogEntry{},
+			wantErr: true,
+		},
+		{
+			name:    "Check invalid json",
+			data:    `{`,
+			want:    []CatalogEntry{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := NewCatalog()
+			if err := catalog.LoadJSON([]byte(tt.data)); (err != nil) != tt.wantErr {
+				t.Errorf("LoadJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := catalog.Entries(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Entries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
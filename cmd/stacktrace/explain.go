@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// runExplain prints the catalog documentation of an error code.
+func runExplain(args []string, stdout, stderr io.Writer) error {
+	var files catalogFiles
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Var(&files, "catalog", "JSON catalog `file` to load, may be repeated")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("explain: exactly one error code expected")
+	}
+
+	catalog, err := loadCatalog(files)
+	if err != nil {
+		return fmt.Errorf("explain: %w", err)
+	}
+	code := stacktrace.Code(fs.Arg(0))
+	entry, ok := catalog.Lookup(code)
+	if !ok {
+		return fmt.Errorf("explain: unknown error code %q", code)
+	}
+	writeExplanation(stdout, entry)
+	return nil
+}
+
+// writeExplanation writes the documentation of the catalog entry.
+func writeExplanation(w io.Writer, entry stacktrace.CatalogEntry) {
+	header := string(entry.Code)
+	if entry.Title != "" {
+		header = fmt.Sprintf("%s: %s", header, entry.Title)
+	}
+	fmt.Fprintln(w, header)
+	if entry.Type != "" {
+		fmt.Fprintf(w, "type: %s\n", entry.Type)
+	}
+	if entry.Severity != "" {
+		fmt.Fprintf(w, "severity: %s\n", entry.Severity)
+	}
+	if entry.Description != "" {
+		fmt.Fprintf(w, "\n%s\n", strings.TrimRight(entry.Description, "\n"))
+	}
+	if len(entry.Examples) > 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for _, example := range entry.Examples {
+			fmt.Fprintln(w)
+			for _, line := range strings.Split(strings.TrimRight(example, "\n"), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+	if entry.HelpURL != "" {
+		fmt.Fprintf(w, "\nSee %s\n", entry.HelpURL)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	catalog := filepath.Join(t.TempDir(), "catalog.json")
+	data := `[{
+		"code": "RAML1023",
+		"title": "Unknown property",
+		"type": "validating",
+		"severity": "warning",
+		"description": "The property is not declared by the type.",
+		"examples": ["type: object\nproperties:\n  name: string"],
+		"helpUrl": "https://example.com/RAML1023"
+	}]`
+	if err := os.WriteFile(catalog, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	yamlCatalog := filepath.Join(t.TempDir(), "catalog.yaml")
+	yamlData := "- code: RAML1024\n" +
+		"  title: Missing property\n" +
+		"  severity: error\n" +
+		"  helpUrl: https://example.com/RAML1024\n"
+	if err := os.WriteFile(yamlCatalog, []byte(yamlData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{
+			name:     "Check explain",
+			args:     []string{"explain", "-catalog", catalog, "RAML1023"},
+			wantCode: 0,
+			wantStdout: "RAML1023: Unknown property\n" +
+				"type: validating\n" +
+				"severity: warning\n" +
+				"\n" +
+				"The property is not declared by the type.\n" +
+				"\n" +
+				"Examples:\n" +
+				"\n" +
+				"    type: object\n" +
+				"    properties:\n" +
+				"      name: string\n" +
+				"\n" +
+				"See https://example.com/RAML1023\n",
+		},
+		{
+			name:     "Check YAML catalog",
+			args:     []string{"explain", "-catalog", catalog, "-catalog", yamlCatalog, "RAML1024"},
+			wantCode: 0,
+			wantStdout: "RAML1024: Missing property\n" +
+				"severity: error\n" +
+				"\n" +
+				"See https://example.com/RAML1024\n",
+		},
+		{
+			name:     "Check unknown code",
+			args:     []string{"explain", "-catalog", catalog, "RAML0000"},
+			wantCode: 1,
+		},
+		{
+			name:     "Check missing catalog",
+			args:     []string{"explain", "RAML1023"},
+			wantCode: 1,
+		},
+		{
+			name:     "Check missing catalog file",
+			args:     []string{"explain", "-catalog", filepath.Join(t.TempDir(), "missing.json"), "RAML1023"},
+			wantCode: 1,
+		},
+		{
+			name:     "Check missing code",
+			args:     []string{"explain"},
+			wantCode: 1,
+		},
+		{
+			name:     "Check unknown command",
+			args:     []string{"describe"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.wantCode {
+				t.Errorf("run() = %v, want %v, stderr: %s", got, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", got, tt.wantStdout)
+			}
+		})
+	}
+}
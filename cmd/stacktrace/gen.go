@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"strconv"
+	"text/template"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// runGen generates typed error constructors from a catalog file.
+func runGen(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	catalogFile := fs.String("catalog", "", "JSON or YAML catalog `file` to generate the constructors from")
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "`name` of the generated package, defaults to $GOPACKAGE")
+	output := fs.String("o", "", "output `file`, defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *catalogFile == "" {
+		return errors.New("gen: -catalog is required")
+	}
+	if *pkg == "" {
+		return errors.New("gen: -package is required")
+	}
+
+	catalog, err := loadCatalog([]string{*catalogFile})
+	if err != nil {
+		return fmt.Errorf("gen: %w", err)
+	}
+	src, err := generate(*pkg, catalog.Entries())
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// genParam is a parameter of a generated constructor.
+type genParam struct {
+	// Name is the name of the template parameter.
+	Name string
+	// Ident is the Go name of the parameter.
+	Ident string
+	Type  string
+}
+
+// genReserved are the identifiers used in the signatures and the bodies of the generated constructors.
+// The parameters with these names, or with the names of the predeclared identifiers, e.g. "len" or "string",
+// are renamed so that they do not shadow them.
+var genReserved = map[string]bool{
+	"stacktrace": true,
+	"opts":       true,
+	"st":         true,
+}
+
+// paramIdent returns the Go name of the template parameter, the parameters shadowing
+// the identifiers used by the generated code get the "Arg" suffix, e.g. "optsArg".
+func paramIdent(name string, reserved map[string]bool) string {
+	if genReserved[name] || reserved[name] || types.Universe.Lookup(name) != nil {
+		return name + "Arg"
+	}
+	return name
+}
+
+// genError is a generated error kind.
+type genError struct {
+	stacktrace.CatalogEntry
+	Params []genParam
+}
+
+// generate returns the formatted Go source of the constructors of the given catalog entries.
+func generate(pkg string, entries []stacktrace.CatalogEntry) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("gen: invalid package name %q", pkg)
+	}
+	// the package-level identifiers used in the bodies of the constructors
+	reserved := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if entry.Name != "" {
+			reserved["Code"+entry.Name] = true
+		}
+	}
+	errs := make([]genError, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name == "" {
+			continue
+		}
+		if !token.IsIdentifier(entry.Name) || !token.IsExported(entry.Name) {
+			return nil, fmt.Errorf("gen: %s: name %q is not an exported Go identifier", entry.Code, entry.Name)
+		}
+		names, err := stacktrace.TemplateParams(entry.Message)
+		if err != nil {
+			return nil, fmt.Errorf("gen: %s: %w", entry.Code, err)
+		}
+		params := make([]genParam, 0, len(names))
+		idents := make(map[string]string, len(names))
+		for _, name := range names {
+			if !token.IsIdentifier(name) || token.IsKeyword(name) {
+				return nil, fmt.Errorf("gen: %s: parameter %q is not a valid Go identifier", entry.Code, name)
+			}
+			ident := paramIdent(name, reserved)
+			if other, ok := idents[ident]; ok {
+				return nil, fmt.Errorf("gen: %s: parameters %q and %q have the same Go name %q", entry.Code, other, name, ident)
+			}
+			idents[ident] = name
+			typ := entry.Params[name]
+			if typ == "" {
+				typ = "string"
+			}
+			params = append(params, genParam{Name: name, Ident: ident, Type: typ})
+		}
+		errs = append(errs, genError{CatalogEntry: entry, Params: params})
+	}
+	if len(errs) == 0 {
+		return nil, errors.New("gen: no catalog entries with a name")
+	}
+
+	var buf bytes.Buffer
+	if err := genTemplate.Execute(&buf, struct {
+		Package string
+		Errors  []genError
+	}{Package: pkg, Errors: errs}); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("gen: format generated source: %w", err)
+	}
+	return src, nil
+}
+
+var genTemplate = template.Must(template.New("gen").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`// Code generated by "stacktrace gen". DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"github.com/acronis/go-stacktrace"
+)
+
+// Error codes.
+const (
+{{- range .Errors }}
+	Code{{ .Name }} stacktrace.Code = {{ quote (print .Code) }}
+{{- end }}
+)
+
+// Sentinels matching the errors with the same code using errors.Is.
+var (
+{{- range .Errors }}
+	Err{{ .Name }} = stacktrace.NewSentinel(Code{{ .Name }})
+{{- end }}
+)
+
+func init() {
+	stacktrace.Register(
+{{- range .Errors }}
+		stacktrace.CatalogEntry{
+			Code: Code{{ .Name }},
+			{{- with .Title }}
+			Title: {{ quote . }},
+			{{- end }}
+			{{- with .Type }}
+			Type: {{ quote (print .) }},
+			{{- end }}
+			{{- with .Severity }}
+			Severity: {{ quote (print .) }},
+			{{- end }}
+			{{- with .Description }}
+			Description: {{ quote . }},
+			{{- end }}
+			{{- with .Examples }}
+			Examples: []string{ {{- range . }}{{ quote . }}, {{ end -}} },
+			{{- end }}
+			{{- with .HelpURL }}
+			HelpURL: {{ quote . }},
+			{{- end }}
+		},
+{{- end }}
+	)
+}
+{{ range .Errors }}
+// New{{ .Name }} creates a new {{ .Code }} error{{ with .Title }}: {{ . }}{{ end }}.
+// The options are applied before the code, so their type and severity override the ones of the catalog entry.
+{{- if .Params }}
+// The parameters are the arguments of the message template kept in Args, they are not added to the Info.
+{{- end }}
+func New{{ .Name }}({{ range .Params }}{{ .Ident }} {{ .Type }}, {{ end }}opts ...stacktrace.Option) *stacktrace.StackTrace {
+	st := stacktrace.NewT(
+		{{ quote .Message }},
+		stacktrace.Args{ {{- range .Params }}{{ quote .Name }}: {{ .Ident }}, {{ end -}} },
+		opts...,
+	)
+	stacktrace.WithCode(Code{{ .Name }}).Apply(st)
+	return st
+}
+{{ end }}`))
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestGenerate(t *testing.T) {
+	entries := []stacktrace.CatalogEntry{
+		{
+			Code:     "RAML1023",
+			Name:     "UnknownProperty",
+			Title:    "Unknown property",
+			Type:     "validating",
+			Severity: "error",
+			Message:  "property {name} is not allowed at {line}",
+			Params:   map[string]string{"line": "int"},
+		},
+		{
+			Code:  "RAML0001",
+			Title: "Not generated",
+		},
+	}
+	src, err := generate("raml", entries)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "errors_gen.go", src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	for _, want := range []string{
+		`CodeUnknownProperty stacktrace.Code = "RAML1023"`,
+		`ErrUnknownProperty = stacktrace.NewSentinel(CodeUnknownProperty)`,
+		`func NewUnknownProperty(name string, line int, opts ...stacktrace.Option) *stacktrace.StackTrace {`,
+		`"property {name} is not allowed at {line}"`,
+		`stacktrace.Args{"name": name, "line": line}`,
+		`opts...,`,
+		`stacktrace.WithCode(CodeUnknownProperty).Apply(st)`,
+		`// The parameters are the arguments of the message template kept in Args, they are not added to the Info.`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source does not contain %q:\n%s", want, src)
+		}
+	}
+	// the parameters are passed as the template arguments only, the Info would render them twice
+	if strings.Contains(string(src), "WithInfo") {
+		t.Errorf("generated source passes the parameters as Info:\n%s", src)
+	}
+	if strings.Contains(string(src), "RAML0001") {
+		t.Errorf("generated source contains the entry without a name:\n%s", src)
+	}
+
+	if _, err := generate("raml", []stacktrace.CatalogEntry{{Code: "A", Name: "unexported"}}); err == nil {
+		t.Errorf("generate() expected error for unexported name")
+	}
+	if _, err := generate("raml", []stacktrace.CatalogEntry{
+		{Code: "A", Name: "Duplicated", Message: "{opts} {optsArg}"},
+	}); err == nil {
+		t.Errorf("generate() expected error for parameters with the same Go name")
+	}
+}
+
+// genTestSource is the test of the package generated by TestGenerate_Compile.
+const genTestSource = `package raml
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestGenerated(t *testing.T) {
+	err := NewUnknownProperty("baseUri", "a", "b", "c", "d", "e", "f")
+	if *err.Type != "validating" || *err.Severity != "error" || *err.Code != CodeUnknownProperty {
+		t.Errorf("type = %v, severity = %v, code = %v", *err.Type, *err.Severity, *err.Code)
+	}
+	if got, want := NewMissingProperty("title").Error(), "[RAML1024]: property title is required"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if !errors.Is(err, ErrUnknownProperty) || errors.Is(err, ErrMissingProperty) {
+		t.Errorf("errors.Is() does not match the sentinels")
+	}
+
+	err = NewUnknownProperty("baseUri", "a", "b", "c", "d", "e", "f",
+		stacktrace.WithType("parsing"), stacktrace.WithSeverity(stacktrace.SeverityWarning))
+	if *err.Type != "parsing" || *err.Severity != stacktrace.SeverityWarning {
+		t.Errorf("options do not override the catalog: type = %v, severity = %v", *err.Type, *err.Severity)
+	}
+}
+`
+
+func TestGenerate_Compile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiling the generated source is skipped in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command is not found")
+	}
+	entries := []stacktrace.CatalogEntry{
+		{
+			Code:     "RAML1023",
+			Name:     "UnknownProperty",
+			Type:     "validating",
+			Severity: "error",
+			// the parameters shadowing the identifiers of the generated code are renamed
+			Message: "property {name} is not allowed: {opts} {stacktrace} {st} {len} {string} {CodeMissingProperty}",
+		},
+		{
+			Code:    "RAML1024",
+			Name:    "MissingProperty",
+			Message: "property {name} is required",
+		},
+	}
+	src, err := generate("raml", entries)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+
+	// the package is generated inside the module to build it against the current sources
+	dir, err := os.MkdirTemp(".", "_gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	if err := os.WriteFile(filepath.Join(dir, "errors_gen.go"), src, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "errors_gen_test.go"), []byte(genTestSource), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("go", "test", "./"+filepath.Base(dir)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("generated source does not pass its test: %v\n%s\n%s", err, out, src)
+	}
+}
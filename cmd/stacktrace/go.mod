@@ -0,0 +1,11 @@
+module github.com/acronis/go-stacktrace/cmd/stacktrace
+
+go 1.22.6
+
+require (
+	github.com/acronis/go-stacktrace v0.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// the catalog and the error codes are released in v0.6.0, the local module is used until it is tagged
+replace github.com/acronis/go-stacktrace => ../../
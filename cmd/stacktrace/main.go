@@ -0,0 +1,127 @@
+// Command stacktrace provides tooling around stacktrace error catalogs.
+//
+// Usage:
+//
+//	stacktrace explain -catalog file [-catalog file]... CODE
+//	stacktrace gen -catalog file [-package name] [-o file]
+//
+// The explain command prints the documentation of an error code. The entries
+// of the files given with -catalog are registered in order, the later files
+// replace the entries with the same code.
+//
+// The catalog files are JSON or YAML arrays of catalog entries, the files with
+// the .yaml or .yml extension are decoded as YAML.
+//
+// The gen command generates typed error constructors and errors.Is sentinels
+// for the catalog entries having a name. It is intended to be used with go generate:
+//
+//	//go:generate go run github.com/acronis/go-stacktrace/cmd/stacktrace gen -catalog errors.json -o errors_gen.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// catalogFiles is a flag.Value collecting the -catalog flags.
+type catalogFiles []string
+
+// String implements the flag.Value interface.
+func (c *catalogFiles) String() string {
+	return strings.Join(*c, ",")
+}
+
+// Set implements the flag.Value interface.
+func (c *catalogFiles) Set(v string) error {
+	*c = append(*c, v)
+	return nil
+}
+
+// loadCatalog loads the given catalog files.
+func loadCatalog(files []string) (*stacktrace.Catalog, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no catalog file, use -catalog")
+	}
+	catalog := stacktrace.NewCatalog()
+	for _, file := range files {
+		if err := loadCatalogFile(catalog, os.DirFS(filepath.Dir(file)), filepath.Base(file)); err != nil {
+			return nil, err
+		}
+	}
+	return catalog, nil
+}
+
+// loadCatalogFile registers the entries of the JSON or YAML catalog file with the given name from fsys.
+func loadCatalogFile(catalog *stacktrace.Catalog, fsys fs.FS, name string) error {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+	default:
+		return catalog.LoadFS(fsys, name)
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("read catalog: %w", err)
+	}
+	// the YAML entries are converted to JSON to share the field names and the checks of LoadJSON
+	var entries []any
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("%s: decode catalog: %w", name, err)
+	}
+	if data, err = json.Marshal(entries); err != nil {
+		return fmt.Errorf("%s: decode catalog: %w", name, err)
+	}
+	if err := catalog.LoadJSON(data); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	fmt.Fprintln(w, "  stacktrace explain -catalog file [-catalog file]... CODE")
+	fmt.Fprintln(w, "  stacktrace gen -catalog file [-package name] [-o file]")
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	var err error
+	switch args[0] {
+	case "explain":
+		err = runExplain(args[1:], stdout, stderr)
+	case "gen":
+		err = runGen(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "stacktrace: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(stderr, "stacktrace: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,101 @@
+package stacktrace
+
+import (
+	"context"
+	"sync"
+)
+
+// ContextExtractor returns the value of the context to copy into the Info of the errors.
+// It returns false if the context has no value.
+type ContextExtractor func(ctx context.Context) (any, bool)
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   = make(map[string]ContextExtractor)
+)
+
+// RegisterContextExtractor registers the extractor of the context value copied into the Info under the given key,
+// e.g. a request ID, a tenant or a trace ID. The nil extractor unregisters the key.
+func RegisterContextExtractor(key string, extractor ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	if extractor == nil {
+		delete(extractors, key)
+		return
+	}
+	extractors[key] = extractor
+}
+
+// RegisterContextKey registers the extractor of the value stored in the context with the given context key,
+// see context.WithValue. The nil values are skipped.
+func RegisterContextKey(key string, ctxKey any) {
+	RegisterContextExtractor(key, func(ctx context.Context) (any, bool) {
+		v := ctx.Value(ctxKey)
+		return v, v != nil
+	})
+}
+
+// contextInfo returns the Info entries extracted from the context by the registered extractors.
+func contextInfo(ctx context.Context) map[string]any {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	result := make(map[string]any, len(extractors))
+	for key, extractor := range extractors {
+		if v, ok := extractor(ctx); ok {
+			result[key] = v
+		}
+	}
+	return result
+}
+
+type optErrContext struct {
+	Ctx context.Context
+}
+
+func (o optErrContext) Apply(e *StackTrace) {
+	if o.Ctx == nil {
+		return
+	}
+	for key, value := range contextInfo(o.Ctx) {
+		// the Info set explicitly has priority
+		if !e.Info.Has(key) {
+			e.Info.Add(key, Stringer(value))
+		}
+	}
+}
+
+// WithContext copies the values extracted from the context by the registered extractors into the Info of the error.
+// The Info entries already set are kept.
+func WithContext(ctx context.Context) Option {
+	return optErrContext{Ctx: ctx}
+}
+
+// NewCtx creates a new StackTrace with the values extracted from the context in its Info.
+func NewCtx(ctx context.Context, message string, opts ...Option) *StackTrace {
+	return New(message, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// WrapCtx wraps the given error like Wrap and copies the values extracted from the context into its Info.
+// A StackTrace is copied before, so the StackTraces shared between the requests are not modified.
+func WrapCtx(ctx context.Context, err error, opts ...Option) *StackTrace {
+	if st, ok := Unwrap(err); ok {
+		err = st.cloneWrapped()
+	}
+	return Wrap(err, append(opts[:len(opts):len(opts)], WithContext(ctx))...)
+}
+
+// cloneWrapped returns a copy of the StackTrace and of its wrapped StackTraces with their own Info,
+// which the options can modify. The lists are shared.
+func (st *StackTrace) cloneWrapped() *StackTrace {
+	if st == nil {
+		return nil
+	}
+	result := *st
+	// the accessors of StructInfo initialize its map, so it is read directly
+	result.Info = StructInfo{}
+	for key, value := range st.Info.info {
+		result.Info.Add(key, value)
+	}
+	result.Wrapped = st.Wrapped.cloneWrapped()
+	return &result
+}
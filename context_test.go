@@ -0,0 +1,98 @@
+package stacktrace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type testCtxKey string
+
+func TestNewCtx(t *testing.T) {
+	RegisterContextKey("request_id", testCtxKey("request"))
+	RegisterContextExtractor("tenant", func(ctx context.Context) (any, bool) {
+		tenant, ok := ctx.Value(testCtxKey("tenant")).(string)
+		return tenant, ok && tenant != ""
+	})
+	defer RegisterContextExtractor("request_id", nil)
+	defer RegisterContextExtractor("tenant", nil)
+
+	ctx := context.WithValue(context.Background(), testCtxKey("request"), "req-1")
+	ctx = context.WithValue(ctx, testCtxKey("tenant"), "acme")
+
+	tests := []struct {
+		name string
+		st   *StackTrace
+		want map[string]string
+	}{
+		{
+			name: "Test NewCtx",
+			st:   NewCtx(ctx, "failed"),
+			want: map[string]string{"request_id": "req-1", "tenant": "acme"},
+		},
+		{
+			name: "Test explicit info is kept",
+			st:   NewCtx(ctx, "failed", WithInfo("tenant", "other")),
+			want: map[string]string{"request_id": "req-1", "tenant": "other"},
+		},
+		{
+			name: "Test missing values",
+			st:   NewCtx(context.WithValue(context.Background(), testCtxKey("tenant"), ""), "failed"),
+			want: map[string]string{},
+		},
+		{
+			name: "Test WrapCtx of a plain error",
+			st:   WrapCtx(ctx, errors.New("failed")),
+			want: map[string]string{"request_id": "req-1", "tenant": "acme"},
+		},
+		{
+			name: "Test WrapCtx of a StackTrace",
+			st:   WrapCtx(ctx, New("failed", WithInfo("property", "baseUri"))),
+			want: map[string]string{"property": "baseUri", "request_id": "req-1", "tenant": "acme"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, key := range tt.st.Info.Keys() {
+				got[key] = tt.st.Info.StringBy(key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Info = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapCtx_Shared(t *testing.T) {
+	RegisterContextKey("request_id", testCtxKey("request"))
+	defer RegisterContextExtractor("request_id", nil)
+
+	shared := New("not found", WithInfo("property", "baseUri")).Wrap(New("no file"))
+	results := make([]*StackTrace, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.WithValue(context.Background(), testCtxKey("request"), fmt.Sprintf("req-%d", i))
+			results[i] = WrapCtx(ctx, shared, WithType("reading"))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, st := range results {
+		if got, want := st.Info.StringBy("request_id"), fmt.Sprintf("req-%d", i); got != want {
+			t.Errorf("WrapCtx() request_id = %v, want %v", got, want)
+		}
+		if got := st.Info.StringBy("property"); got != "baseUri" {
+			t.Errorf("WrapCtx() property = %v, want baseUri", got)
+		}
+	}
+	if shared.Info.Has("request_id") || shared.Type != nil || shared.Wrapped.Type != nil {
+		t.Errorf("WrapCtx() modified the shared StackTrace: %v", shared)
+	}
+}
@@ -0,0 +1,124 @@
+package stacktrace
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ExitPolicy maps the diagnostics to the process exit codes.
+type ExitPolicy struct {
+	// Severities maps the severities to the exit codes.
+	Severities map[Severity]int
+	// Codes maps the error codes to the exit codes, they have priority over the severities.
+	Codes map[Code]int
+	// Default is the exit code of the diagnostics with an unmapped or without severity
+	// and of the errors which are not StackTraces.
+	Default int
+	// SeverityPolicy rewrites the severities of the diagnostics before they are mapped, nil keeps them.
+	SeverityPolicy *SeverityPolicy
+}
+
+// DefaultExitPolicy returns the policy exiting with 0 on warnings only, 1 on errors and 2 on critical errors.
+func DefaultExitPolicy() *ExitPolicy {
+	return &ExitPolicy{
+		Severities: map[Severity]int{
+			SeverityInfo:     0,
+			SeverityWarning:  0,
+			SeverityError:    1,
+			SeverityCritical: 2,
+		},
+		Default: 1,
+	}
+}
+
+// chainExitCode returns the exit code of a path of the tree, i.e. of a trace.
+// It is the highest exit code of its codes if any is mapped, the exit code of its highest severity otherwise.
+// It returns false if none of its nodes has a mapped code or a severity and the path is not reported as a trace,
+// i.e. its innermost node is a locationless container of a list.
+func (p *ExitPolicy) chainExitCode(chain []*StackTrace) (int, bool) {
+	var severity *Severity
+	result, coded := 0, false
+	for _, st := range chain {
+		if st.Code != nil {
+			if code, ok := p.Codes[*st.Code]; ok && (!coded || code > result) {
+				result, coded = code, true
+			}
+		}
+		if st.Severity != nil && (severity == nil || SeverityRank(*st.Severity) > SeverityRank(*severity)) {
+			severity = st.Severity
+		}
+	}
+	if coded {
+		return result, true
+	}
+	if severity == nil {
+		innermost := chain[len(chain)-1]
+		return p.Default, innermost.Location != nil || len(innermost.List) == 0
+	}
+	if code, ok := p.Severities[*severity]; ok {
+		return code, true
+	}
+	return p.Default, true
+}
+
+// exitCode returns the highest exit code of the StackTrace, its wrapped StackTraces and their lists.
+// The prefix are the nodes wrapping the StackTrace, they are part of its paths as in GetTraces.
+func (p *ExitPolicy) exitCode(st *StackTrace, prefix []*StackTrace) int {
+	chain := prefix[:len(prefix):len(prefix)]
+	for w := st; w != nil; w = w.Wrapped {
+		chain = append(chain, w)
+	}
+	result := 0
+	if code, ok := p.chainExitCode(chain); ok {
+		result = code
+	}
+	for i := len(prefix); i < len(chain); i++ {
+		for _, elem := range chain[i].List {
+			if code := p.exitCode(elem, chain[:i]); code > result {
+				result = code
+			}
+		}
+	}
+	return result
+}
+
+// ExitCode returns the process exit code of the error, the highest exit code of the nodes of its tree,
+// including the locationless containers with a severity. It returns 0 for the nil error.
+// The nil policy is the DefaultExitPolicy.
+func ExitCode(err error, policy *ExitPolicy) int {
+	if err == nil {
+		return 0
+	}
+	if policy == nil {
+		policy = DefaultExitPolicy()
+	}
+	st, ok := Unwrap(err)
+	if !ok {
+		return policy.Default
+	}
+	return policy.exitCode(policy.SeverityPolicy.Apply(st), nil)
+}
+
+// osExit is os.Exit, replaced in the tests.
+var osExit = os.Exit
+
+// Exit writes the report of the error to w and exits the process with its exit code.
+// The report contains a line per trace with the severities rewritten by the SeverityPolicy of the policy,
+// the errors which are not StackTraces are written as is. The nil policy is the DefaultExitPolicy.
+func Exit(w io.Writer, err error, policy *ExitPolicy) {
+	if policy == nil {
+		policy = DefaultExitPolicy()
+	}
+	if err != nil {
+		if st, ok := Unwrap(err); ok {
+			traces := policy.SeverityPolicy.Apply(st).GetTraces()
+			for i := range traces {
+				fmt.Fprintln(w, traces[i].String())
+			}
+		} else {
+			fmt.Fprintln(w, err.Error())
+		}
+	}
+	osExit(ExitCode(err, policy))
+}
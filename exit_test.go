@@ -0,0 +1,148 @@
+package stacktrace
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		policy *ExitPolicy
+		want   int
+	}{
+		{
+			name: "Check nil error",
+			err:  nil,
+			want: 0,
+		},
+		{
+			name: "Check warnings only",
+			err: New("validation failed").
+				Append(New("deprecated", WithSeverity(SeverityWarning), WithLocation("api.raml"))).
+				Append(NewWrapped("in library", New("unused", WithSeverity(SeverityInfo), WithLocation("lib.raml")))),
+			want: 0,
+		},
+		{
+			name: "Check errors",
+			err: New("validation failed").
+				Append(New("deprecated", WithSeverity(SeverityWarning), WithLocation("api.raml"))).
+				Append(New("invalid", WithSeverity(SeverityError), WithLocation("api.raml"))),
+			want: 1,
+		},
+		{
+			name: "Check critical",
+			err: New("validation failed").
+				Append(New("invalid", WithSeverity(SeverityError), WithLocation("api.raml"))).
+				Append(New("crashed", WithSeverity(SeverityCritical), WithLocation("api.raml"))),
+			want: 2,
+		},
+		{
+			name: "Check critical container",
+			err: New("fatal", WithSeverity(SeverityCritical)).
+				Append(New("w", WithSeverity(SeverityWarning), WithLocation("a.raml"))),
+			want: 2,
+		},
+		{
+			name: "Check critical wrapped in list",
+			err: New("validation failed").
+				Append(NewWrapped("in library", New("crashed", WithSeverity(SeverityCritical)).
+					Append(New("w", WithSeverity(SeverityWarning), WithLocation("lib.raml"))))),
+			want: 2,
+		},
+		{
+			name: "Check code of wrapping node",
+			err: NewWrapped("in library", New("validation failed").
+				Append(New("w", WithSeverity(SeverityWarning), WithLocation("lib.raml")))).SetCode("RAML1023"),
+			policy: &ExitPolicy{
+				Severities: DefaultExitPolicy().Severities,
+				Codes:      map[Code]int{"RAML1023": 3},
+			},
+			want: 3,
+		},
+		{
+			name: "Check without severity",
+			err:  New("failed"),
+			want: 1,
+		},
+		{
+			name: "Check not a stacktrace",
+			err:  fmt.Errorf("failed"),
+			want: 1,
+		},
+		{
+			name: "Check code rule",
+			err: New("validation failed").
+				Append(New("invalid", WithSeverity(SeverityError), WithCode("RAML1023"), WithLocation("api.raml"))),
+			policy: &ExitPolicy{
+				Severities: DefaultExitPolicy().Severities,
+				Codes:      map[Code]int{"RAML1023": 3},
+			},
+			want: 3,
+		},
+		{
+			name: "Check severity policy",
+			err: New("validation failed").
+				Append(New("deprecated", WithSeverity(SeverityWarning), WithLocation("api.raml"))),
+			policy: &ExitPolicy{
+				Severities:     DefaultExitPolicy().Severities,
+				SeverityPolicy: &SeverityPolicy{rules: []*SeverityRule{{Severity: SeverityWarning, To: SeverityError}}},
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err, tt.policy); got != tt.want {
+				t.Errorf("ExitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExit(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	var code int
+	osExit = func(c int) { code = c }
+
+	var buf bytes.Buffer
+	err := New("validation failed").
+		Append(New("deprecated", WithSeverity(SeverityWarning), WithType("deprecation"), WithLocation("api.raml"))).
+		Append(NewWrapped("in library", New("invalid", WithSeverity(SeverityError), WithCode("RAML1023"), WithLocation("lib.raml"), WithPosition(NewPosition(3, 4)))))
+	Exit(&buf, err, nil)
+
+	want := "warning: deprecation: api.raml:1: deprecated\n" +
+		"error: in library: [RAML1023]: lib.raml:3:4: invalid\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Exit() report = %q, want %q", got, want)
+	}
+	if code != 1 {
+		t.Errorf("Exit() code = %v, want 1", code)
+	}
+}
+
+func TestExit_SeverityPolicy(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	var code int
+	osExit = func(c int) { code = c }
+
+	severityPolicy, err := NewSeverityPolicy(WarningsAsErrors())
+	if err != nil {
+		t.Fatalf("NewSeverityPolicy() error = %v", err)
+	}
+	policy := DefaultExitPolicy()
+	policy.SeverityPolicy = severityPolicy
+
+	var buf bytes.Buffer
+	Exit(&buf, New("deprecated", WithSeverity(SeverityWarning), WithType("deprecation"), WithLocation("api.raml")), policy)
+
+	// the report shows the severity the exit code is computed from
+	if got, want := buf.String(), "error: deprecation: api.raml:1: deprecated\n"; got != want {
+		t.Errorf("Exit() report = %q, want %q", got, want)
+	}
+	if code != 1 {
+		t.Errorf("Exit() code = %v, want 1", code)
+	}
+}
@@ -0,0 +1,185 @@
+package stacktrace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// FingerprintOpt is an option of the fingerprint computation.
+type FingerprintOpt interface {
+	Apply(o *FingerprintOptions)
+}
+
+// FingerprintOptions are the options of the fingerprint computation.
+type FingerprintOptions struct {
+	// Root is the prefix removed from the locations, so the fingerprints do not depend on the checkout directory.
+	Root string
+	// WrapPath includes the wrapped StackTraces into the fingerprint.
+	WrapPath bool
+	// ExcludePosition excludes the line and the column from the fingerprint.
+	ExcludePosition bool
+}
+
+// NewFingerprintOptions creates new default fingerprint options.
+func NewFingerprintOptions() *FingerprintOptions {
+	return &FingerprintOptions{}
+}
+
+type fingerprintRootOpt struct {
+	root string
+}
+
+func (o fingerprintRootOpt) Apply(opts *FingerprintOptions) {
+	opts.Root = o.root
+}
+
+// WithFingerprintRoot makes the locations relative to the given root directory.
+func WithFingerprintRoot(root string) FingerprintOpt {
+	return fingerprintRootOpt{root: root}
+}
+
+type fingerprintWrapPathOpt struct{}
+
+func (fingerprintWrapPathOpt) Apply(opts *FingerprintOptions) {
+	opts.WrapPath = true
+}
+
+// WithFingerprintWrapPath includes the wrapped StackTraces into the fingerprint.
+func WithFingerprintWrapPath() FingerprintOpt {
+	return fingerprintWrapPathOpt{}
+}
+
+type fingerprintExcludePositionOpt struct{}
+
+func (fingerprintExcludePositionOpt) Apply(opts *FingerprintOptions) {
+	opts.ExcludePosition = true
+}
+
+// WithoutFingerprintPosition excludes the line and the column from the fingerprint.
+func WithoutFingerprintPosition() FingerprintOpt {
+	return fingerprintExcludePositionOpt{}
+}
+
+// Fingerprint returns the stable identity of the StackTrace.
+// It is a hash of the type, the code, the location, the position and the normalized message of the StackTrace;
+// the message template is used instead of the message if it is set, the Info is not included.
+// With WithFingerprintWrapPath the wrapped StackTraces are included as well.
+func (st *StackTrace) Fingerprint(opts ...FingerprintOpt) string {
+	o := NewFingerprintOptions()
+	for _, opt := range opts {
+		opt.Apply(o)
+	}
+	return st.fingerprint(o)
+}
+
+// fingerprint returns the fingerprint of the StackTrace with the given options.
+// It is computed from the stack entries of the StackTrace and of its wrapped StackTraces, as the fingerprints of the traces.
+func (st *StackTrace) fingerprint(o *FingerprintOptions) string {
+	tracesOpts := NewTracesOptions()
+	trace := NewTrace()
+	trace.Stack = append(trace.Stack, *st.newStack(tracesOpts))
+	if o.WrapPath {
+		for wrapped := st.Wrapped; wrapped != nil; wrapped = wrapped.Wrapped {
+			trace.Stack = append(trace.Stack, *wrapped.newStack(tracesOpts))
+		}
+	}
+	return traceFingerprint(trace, o)
+}
+
+// location returns the location relative to the root with forward slashes, empty if it is nil.
+func (o *FingerprintOptions) location(location *Location) string {
+	if location == nil {
+		return ""
+	}
+	loc := strings.ReplaceAll(location.String(), "\\", "/")
+	if root := strings.TrimSuffix(strings.ReplaceAll(o.Root, "\\", "/"), "/"); root != "" {
+		loc = strings.TrimPrefix(strings.TrimPrefix(loc, root+"/"), "/")
+	}
+	return loc
+}
+
+// hashFields returns the hex encoded hash of the given fields.
+func hashFields(fields ...string) string {
+	h := sha256.New()
+	for _, field := range fields {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+type fingerprintsOpt struct {
+	opts []FingerprintOpt
+}
+
+func (o fingerprintsOpt) Apply(opts *TracesOptions) {
+	opts.Fingerprint = NewFingerprintOptions()
+	for _, opt := range o.opts {
+		opt.Apply(opts.Fingerprint)
+	}
+}
+
+// WithFingerprints sets the fingerprints of the traces computed with the given options.
+// The fingerprint of a trace is the fingerprint of its innermost StackTrace, or of the whole trace
+// with WithFingerprintWrapPath.
+func WithFingerprints(opts ...FingerprintOpt) TracesOpt {
+	return fingerprintsOpt{opts: opts}
+}
+
+var (
+	// spaceRE matches the whitespace runs.
+	spaceRE = regexp.MustCompile(`\s+`)
+	// positionRE matches the positions embedded in the messages, e.g. "line 3" or "3:14".
+	positionRE = regexp.MustCompile(`(?i)\b(line|column|col|offset|position)(\s+)\d+|\b\d+:\d+(:\d+)?\b`)
+	// idRE matches the identifiers embedded in the messages, e.g. UUIDs and long numbers such as timestamps.
+	idRE = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b|\b\d{6,}\b`)
+)
+
+// normalizeMessage normalizes the message for the fingerprints.
+// The whitespace runs are collapsed, and the positions, e.g. "line 3" or "3:14", and the identifiers,
+// i.e. the UUIDs and the numbers of six digits or more, are replaced with "0", so the fingerprint survives
+// the line numbers and the request IDs embedded in the messages. The other numbers are kept, e.g.
+// "expected 2 items" and "expected 3 items" are different diagnostics.
+func normalizeMessage(msg string) string {
+	msg = spaceRE.ReplaceAllString(strings.TrimSpace(msg), " ")
+	msg = positionRE.ReplaceAllStringFunc(msg, func(m string) string {
+		if i := strings.LastIndexAny(m, " \t"); i >= 0 {
+			return m[:i+1] + "0"
+		}
+		return "0"
+	})
+	return idRE.ReplaceAllString(msg, "0")
+}
+
+// stackFingerprint returns the fingerprint of the stack entry chained with the given wrapped fingerprint.
+// The message template has priority over the message, and the original message over the localized
+// and redacted one, so the fingerprint does not depend on the localization and the redaction.
+func stackFingerprint(stack *Stack, o *FingerprintOptions, wrapped string) string {
+	msg := stack.Template
+	if msg == "" {
+		msg = stack.originalMessage()
+	}
+	var pos string
+	if stack.Location != nil && !o.ExcludePosition {
+		pos = stack.Position.String()
+	}
+	return hashFields(stack.Type.String(), stack.Code.String(), o.location(stack.Location), pos, normalizeMessage(msg), wrapped)
+}
+
+// traceFingerprint returns the fingerprint of the trace, i.e. the fingerprint of its innermost stack entry,
+// or of all its stack entries with WrapPath.
+func traceFingerprint(trace *Trace, o *FingerprintOptions) string {
+	if len(trace.Stack) == 0 {
+		return ""
+	}
+	last := len(trace.Stack) - 1
+	fp := stackFingerprint(&trace.Stack[last], o, "")
+	if o.WrapPath {
+		for i := last - 1; i >= 0; i-- {
+			fp = stackFingerprint(&trace.Stack[i], o, fp)
+		}
+	}
+	return fp
+}
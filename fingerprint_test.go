@@ -0,0 +1,117 @@
+package stacktrace
+
+import (
+	"testing"
+)
+
+func TestStackTrace_Fingerprint(t *testing.T) {
+	base := func(opts ...Option) *StackTrace {
+		return New("property baseUri is not allowed", append([]Option{
+			WithType("unknown-property"),
+			WithLocation("/home/ci/specs/api.raml"),
+			WithPosition(NewPosition(3, 1)),
+		}, opts...)...)
+	}
+
+	tests := []struct {
+		name  string
+		a     *StackTrace
+		b     *StackTrace
+		opts  []FingerprintOpt
+		equal bool
+	}{
+		{
+			name:  "Check same diagnostic",
+			a:     base(),
+			b:     base(),
+			equal: true,
+		},
+		{
+			name:  "Check whitespace and info are ignored",
+			a:     base(),
+			b:     New("property  baseUri is not allowed ", WithType("unknown-property"), WithLocation("/home/ci/specs/api.raml"), WithPosition(NewPosition(3, 1)), WithInfo("key", "value")),
+			equal: true,
+		},
+		{
+			name:  "Check position is included",
+			a:     base(),
+			b:     base(WithPosition(NewPosition(4, 1))),
+			equal: false,
+		},
+		{
+			name:  "Check position is excluded",
+			a:     base(),
+			b:     base(WithPosition(NewPosition(4, 1))),
+			opts:  []FingerprintOpt{WithoutFingerprintPosition()},
+			equal: true,
+		},
+		{
+			name:  "Check relative location",
+			a:     base(),
+			b:     base(WithLocation("/builds/42/specs/api.raml")),
+			opts:  []FingerprintOpt{WithFingerprintRoot("/home/ci")},
+			equal: false,
+		},
+		{
+			name:  "Check code",
+			a:     base(),
+			b:     base(WithCode("RAML1023")),
+			equal: false,
+		},
+		{
+			name:  "Check wrap path is excluded",
+			a:     New("context").Wrap(base()),
+			b:     New("context").Wrap(base(WithCode("RAML1023"))),
+			equal: true,
+		},
+		{
+			name:  "Check wrap path is included",
+			a:     New("context").Wrap(base()),
+			b:     New("context").Wrap(base(WithCode("RAML1023"))),
+			opts:  []FingerprintOpt{WithFingerprintWrapPath()},
+			equal: false,
+		},
+		{
+			name:  "Check template over localized message",
+			a:     NewT("property {name} is not allowed", Args{"name": "a"}),
+			b:     NewT("property {name} is not allowed", Args{"name": "b"}),
+			equal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a.Fingerprint(tt.opts...), tt.b.Fingerprint(tt.opts...)
+			if len(a) != 32 {
+				t.Errorf("Fingerprint() = %v, want 32 hex digits", a)
+			}
+			if (a == b) != tt.equal {
+				t.Errorf("Fingerprint() = %v and %v, equal %v", a, b, tt.equal)
+			}
+		})
+	}
+
+	a := base(WithLocation("/home/ci/specs/api.raml")).Fingerprint(WithFingerprintRoot("/home/ci/"))
+	b := base(WithLocation("/builds/42/specs/api.raml")).Fingerprint(WithFingerprintRoot("/builds/42"))
+	if a != b {
+		t.Errorf("Fingerprint() with root = %v and %v, want equal", a, b)
+	}
+}
+
+func TestStackTrace_GetTracesWithFingerprints(t *testing.T) {
+	leaf := New("leaf", WithLocation("api.raml"))
+	st := New("root", WithLocation("api.raml"), WithPosition(NewPosition(2, 1))).Wrap(leaf)
+
+	traces := st.GetTraces(WithFingerprints())
+	if len(traces) != 1 || traces[0].Fingerprint != leaf.Fingerprint() {
+		t.Errorf("GetTraces() fingerprint = %v, want %v", traces, leaf.Fingerprint())
+	}
+
+	traces = st.GetTraces(WithFingerprints(WithFingerprintWrapPath()))
+	if want := st.Fingerprint(WithFingerprintWrapPath()); len(traces) != 1 || traces[0].Fingerprint != want {
+		t.Errorf("GetTraces() fingerprint = %v, want %v", traces, want)
+	}
+
+	if traces := st.GetTraces(); traces[0].Fingerprint != "" {
+		t.Errorf("GetTraces() fingerprint = %v, want empty", traces[0].Fingerprint)
+	}
+}
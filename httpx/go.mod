@@ -0,0 +1,13 @@
+module github.com/acronis/go-stacktrace/httpx
+
+go 1.22.6
+
+require (
+	github.com/acronis/go-stacktrace v0.6.0
+	github.com/acronis/go-stacktrace/slogex v0.6.0
+)
+
+replace (
+	github.com/acronis/go-stacktrace => ../
+	github.com/acronis/go-stacktrace/slogex => ../slogex
+)
@@ -0,0 +1,216 @@
+package httpx
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/acronis/go-stacktrace"
+	"github.com/acronis/go-stacktrace/slogex"
+)
+
+// CorrelationHeader is the default header of the correlation IDs.
+const CorrelationHeader = "X-Correlation-ID"
+
+// CorrelationMember is the problem details extension member of the correlation ID.
+const CorrelationMember = "correlationId"
+
+// TypePanic is the type of the StackTraces of the recovered panics.
+const TypePanic stacktrace.Type = "panic"
+
+// HandlerFunc is an HTTP handler returning an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Middleware recovers the panics of the handlers, logs the errors and writes them as problem details.
+type Middleware struct {
+	// Encoder writes the problem details, the default encoder is sanitized.
+	Encoder *Encoder
+	// Logger logs the errors, defaults to slog.Default().
+	Logger *slog.Logger
+	// Header is the header of the correlation IDs, defaults to CorrelationHeader.
+	// The correlation ID of the request is reused, a new one is generated otherwise.
+	Header string
+	// NewID generates the correlation IDs, defaults to random 16-byte hex strings.
+	NewID func() string
+}
+
+// NewMiddleware creates a new middleware logging to the given logger with a sanitized default encoder.
+func NewMiddleware(logger *slog.Logger) *Middleware {
+	return &Middleware{
+		Encoder: sanitizedEncoder(),
+		Logger:  logger,
+		Header:  CorrelationHeader,
+		NewID:   newID,
+	}
+}
+
+// sanitizedEncoder returns the default encoder omitting the internal details.
+func sanitizedEncoder() *Encoder {
+	encoder := NewEncoder()
+	encoder.Sanitize = true
+	return encoder
+}
+
+// newID returns a random 16-byte hex string.
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Handler returns the handler recovering the panics of next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return m.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// HandlerFunc returns the handler writing the errors returned by h as problem details
+// and recovering its panics.
+func (m *Middleware) HandlerFunc(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := m.correlationID(r)
+		w.Header().Set(m.header(), id)
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			m.writeError(rw, r, id, recovered(v))
+		}()
+		if err := h(rw, r); err != nil {
+			m.writeError(rw, r, id, err)
+		}
+	})
+}
+
+// recovered returns the StackTrace of the recovered panic value.
+// The stack of the goroutine is kept in the Info.
+func recovered(v any) *stacktrace.StackTrace {
+	st := stacktrace.New(fmt.Sprintf("panic: %v", v),
+		stacktrace.WithType(TypePanic),
+		stacktrace.WithSeverity(stacktrace.SeverityCritical),
+		stacktrace.WithInfo("stack", string(debug.Stack())),
+	)
+	if err, ok := v.(error); ok {
+		st.SetErr(err)
+	}
+	return st
+}
+
+// writeError logs the error and writes it as problem details unless the response is already written.
+func (m *Middleware) writeError(w *responseWriter, r *http.Request, id string, err error) {
+	encoder := m.Encoder
+	if encoder == nil {
+		encoder = sanitizedEncoder()
+	}
+	var p *Problem
+	if st, ok := stacktrace.Unwrap(err); ok && st.Type != nil && *st.Type == TypePanic {
+		// the panic values are internal
+		p = &Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+		}
+	} else {
+		p = encoder.Problem(err)
+	}
+
+	logger := m.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	level := slog.LevelInfo
+	if p.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	logger.LogAttrs(r.Context(), level, "request failed",
+		slog.String("correlation_id", id),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", p.Status),
+		slogex.ErrToSlogAttr(err),
+	)
+
+	if w.wroteHeader {
+		return
+	}
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[CorrelationMember] = id
+	if err := WriteProblem(w, p); err != nil {
+		logger.LogAttrs(r.Context(), slog.LevelError, "write problem",
+			slog.String("correlation_id", id),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// correlationID returns the correlation ID of the request or a new one.
+func (m *Middleware) correlationID(r *http.Request) string {
+	if id := r.Header.Get(m.header()); id != "" {
+		return id
+	}
+	if m.NewID != nil {
+		return m.NewID()
+	}
+	return newID()
+}
+
+// header returns the header of the correlation IDs.
+func (m *Middleware) header() string {
+	if m.Header == "" {
+		return CorrelationHeader
+	}
+	return m.Header
+}
+
+// responseWriter records if the response header is written.
+// It forwards the optional interfaces of the underlying writer, e.g. http.Flusher and http.Hijacker.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface, it does nothing if the underlying writer cannot flush.
+func (w *responseWriter) Flush() {
+	if err := http.NewResponseController(w.ResponseWriter).Flush(); err == nil {
+		w.wroteHeader = true
+	}
+}
+
+// Hijack implements the http.Hijacker interface.
+// It returns http.ErrNotSupported if the underlying writer cannot hijack the connection.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
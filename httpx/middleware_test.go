@@ -0,0 +1,234 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		handler    HandlerFunc
+		header     string
+		wantStatus int
+		want       *Problem
+		wantLog    []string
+	}{
+		{
+			name: "Test panic",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				panic("secret state")
+			},
+			wantStatus: http.StatusInternalServerError,
+			want: &Problem{
+				Type:       "about:blank",
+				Title:      "Internal Server Error",
+				Status:     http.StatusInternalServerError,
+				Extensions: map[string]any{CorrelationMember: "id-1"},
+			},
+			wantLog: []string{`"level":"ERROR"`, `panic: secret state`, `"correlation_id":"id-1"`},
+		},
+		{
+			name: "Test returned StackTrace",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return stacktrace.New("invalid document",
+					stacktrace.WithType("validation"),
+					stacktrace.WithInfo("user", "alice"),
+				).Append(stacktrace.New("unknown property",
+					stacktrace.WithType("parsing"),
+					stacktrace.WithLocation("api.raml"),
+					stacktrace.WithInfo("internal", "x"),
+				))
+			},
+			header:     "req-7",
+			wantStatus: http.StatusUnprocessableEntity,
+			want: &Problem{
+				Type:       "urn:stacktrace:validation",
+				Title:      "validation",
+				Status:     http.StatusUnprocessableEntity,
+				Detail:     "invalid document",
+				Errors:     []ProblemError{{Type: "parsing", Location: "api.raml", Line: 1, Message: "unknown property"}},
+				Extensions: map[string]any{CorrelationMember: "req-7"},
+			},
+			wantLog: []string{`"level":"INFO"`, `"correlation_id":"req-7"`, `"message":"unknown property","info":{"internal":"x"}`},
+		},
+		{
+			name: "Test returned error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return errors.New("dial tcp 10.0.0.1:5432")
+			},
+			wantStatus: http.StatusInternalServerError,
+			want: &Problem{
+				Type:       "about:blank",
+				Title:      "Internal Server Error",
+				Status:     http.StatusInternalServerError,
+				Extensions: map[string]any{CorrelationMember: "id-1"},
+			},
+			wantLog: []string{`dial tcp 10.0.0.1:5432`},
+		},
+		{
+			name: "Test no error",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			wantStatus: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			m := NewMiddleware(slog.New(slog.NewJSONHandler(&logs, nil)))
+			m.NewID = func() string { return "id-1" }
+
+			req := httptest.NewRequest(http.MethodGet, "/specs", nil)
+			if tt.header != "" {
+				req.Header.Set(CorrelationHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			m.HandlerFunc(tt.handler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			wantID := tt.header
+			if wantID == "" {
+				wantID = "id-1"
+			}
+			if got := rec.Header().Get(CorrelationHeader); got != wantID {
+				t.Errorf("correlation ID = %q, want %q", got, wantID)
+			}
+			if tt.want == nil {
+				if logs.Len() != 0 {
+					t.Errorf("log = %s, want none", logs.String())
+				}
+				return
+			}
+			got := &Problem{}
+			if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("problem = %+v, want %+v", got, tt.want)
+			}
+			for _, s := range tt.wantLog {
+				if !strings.Contains(logs.String(), s) {
+					t.Errorf("log = %s, want to contain %s", logs.String(), s)
+				}
+			}
+		})
+	}
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	var logs bytes.Buffer
+	m := NewMiddleware(slog.New(slog.NewJSONHandler(&logs, nil)))
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusAccepted)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %s, want empty", rec.Body.String())
+	}
+	if !strings.Contains(logs.String(), "panic: late") {
+		t.Errorf("log = %s, want the panic", logs.String())
+	}
+}
+
+func TestMiddleware_ZeroEncoder(t *testing.T) {
+	m := &Middleware{Encoder: &Encoder{}, Logger: slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))}
+	tests := []struct {
+		name       string
+		handler    HandlerFunc
+		wantStatus int
+	}{
+		{
+			name: "Test panic",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				panic("boom")
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "Test returned StackTrace",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return stacktrace.New("not found", stacktrace.WithType(TypeNotFound))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			m.HandlerFunc(tt.handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// hijackRecorder is a recorder whose connection can be hijacked.
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
+
+func TestMiddleware_ResponseWriter(t *testing.T) {
+	m := NewMiddleware(slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil)))
+	rec := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h := m.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatalf("writer is not a http.Flusher")
+		}
+		flusher.Flush()
+		if err := http.NewResponseController(w).EnableFullDuplex(); !errors.Is(err, http.ErrNotSupported) {
+			t.Errorf("EnableFullDuplex() error = %v, want %v", err, http.ErrNotSupported)
+		}
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatalf("writer is not a http.Hijacker")
+		}
+		if _, _, err := hijacker.Hijack(); err != nil {
+			t.Fatalf("Hijack() error = %v", err)
+		}
+		return errors.New("after hijack")
+	})
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !rec.Flushed || !rec.hijacked {
+		t.Errorf("flushed = %v, hijacked = %v, want both", rec.Flushed, rec.hijacked)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %s, want empty after the hijack", rec.Body.String())
+	}
+
+	plain := m.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if _, _, err := w.(http.Hijacker).Hijack(); !errors.Is(err, http.ErrNotSupported) {
+			t.Errorf("Hijack() error = %v, want %v", err, http.ErrNotSupported)
+		}
+		return nil
+	})
+	plain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,339 @@
+// Package httpx encodes and decodes StackTraces as HTTP problem details (RFC 9457).
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// ContentType is the media type of the problem details.
+const ContentType = "application/problem+json"
+
+// DefaultTypeBase is the prefix of the problem types built from the error codes and types.
+const DefaultTypeBase = "urn:stacktrace:"
+
+// ProblemError is a diagnostic of the problem, the innermost stack entry of a trace.
+type ProblemError struct {
+	Type     string `json:"type,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Location string `json:"location,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+	// Trace is the whole trace of the diagnostic.
+	Trace string `json:"trace,omitempty"`
+}
+
+// Problem is the problem details document.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the error code extension member.
+	Code string `json:"code,omitempty"`
+	// Errors is the diagnostics extension member.
+	Errors []ProblemError `json:"errors,omitempty"`
+	// Extensions are the other extension members.
+	Extensions map[string]any `json:"-"`
+}
+
+// problem is Problem without the JSON methods.
+type problem Problem
+
+// MarshalJSON implements the json.Marshaler interface.
+// The extension members are inlined into the document.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+	members := make(map[string]any, len(p.Extensions))
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	var std map[string]json.RawMessage
+	if err := json.Unmarshal(data, &std); err != nil {
+		return nil, err
+	}
+	for k, v := range std {
+		members[k] = v
+	}
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The unknown members are decoded into the extensions.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*problem)(p)); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for _, k := range []string{"type", "title", "status", "detail", "instance", "code", "errors"} {
+		delete(members, k)
+	}
+	if len(members) > 0 {
+		p.Extensions = members
+	} else {
+		p.Extensions = nil
+	}
+	return nil
+}
+
+// Encoder writes the errors as problem details.
+type Encoder struct {
+	// TypeBase is the prefix of the problem types built from the error codes and types.
+	TypeBase string
+	// Catalog provides the titles and the help URLs of the codes, defaults to stacktrace.DefaultCatalog.
+	Catalog *stacktrace.Catalog
+	// Status returns the HTTP status of the StackTrace, defaults to DefaultStatusMapping.
+	Status func(st *stacktrace.StackTrace) int
+	// TracesOpts are the options of the detail and of the traces written in the errors member,
+	// e.g. the localizer and the redaction policy.
+	TracesOpts []stacktrace.TracesOpt
+	// Sanitize omits the Info of the StackTraces, the traces of the diagnostics
+	// and the messages of the errors which are not StackTraces.
+	Sanitize bool
+}
+
+// NewEncoder creates a new encoder with the default options.
+// The statuses are resolved with DefaultStatusMapping.
+func NewEncoder() *Encoder {
+	return &Encoder{
+		TypeBase: DefaultTypeBase,
+		Status:   DefaultStatusMapping().Resolve,
+	}
+}
+
+// StatusByType returns a status function mapping the StackTrace types to the HTTP statuses.
+// The types without a status are mapped to the fallback.
+func StatusByType(statuses map[stacktrace.Type]int, fallback int) func(st *stacktrace.StackTrace) int {
+	return func(st *stacktrace.StackTrace) int {
+		if st.Type != nil {
+			if status, ok := statuses[*st.Type]; ok {
+				return status
+			}
+		}
+		return fallback
+	}
+}
+
+// Problem returns the problem details of the error.
+// The errors which are not StackTraces are written with the status 500 and their message as the detail.
+// It returns nil for the nil error.
+func (e *Encoder) Problem(err error) *Problem {
+	if err == nil {
+		return nil
+	}
+	st, ok := stacktrace.Unwrap(err)
+	if !ok {
+		p := &Problem{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+		}
+		if !e.Sanitize {
+			p.Detail = err.Error()
+		}
+		return p
+	}
+	if e.Sanitize {
+		st = sanitize(st)
+	}
+
+	p := &Problem{
+		Type:   "about:blank",
+		Status: e.status(st),
+		Detail: st.RenderMessage(e.TracesOpts...),
+	}
+	catalog := e.Catalog
+	if catalog == nil {
+		catalog = stacktrace.DefaultCatalog
+	}
+	var entry stacktrace.CatalogEntry
+	var hasEntry bool
+	if st.Code != nil {
+		entry, hasEntry = catalog.Lookup(*st.Code)
+	}
+	switch {
+	case hasEntry && entry.HelpURL != "":
+		p.Type = entry.HelpURL
+	case st.Code != nil && *st.Code != "":
+		p.Type = e.TypeBase + st.Code.String()
+	case st.Type != nil && *st.Type != "":
+		p.Type = e.TypeBase + st.Type.String()
+	}
+	switch {
+	case hasEntry && entry.Title != "":
+		p.Title = entry.Title
+	case st.Type != nil && *st.Type != "":
+		p.Title = st.Type.String()
+	default:
+		p.Title = http.StatusText(p.Status)
+	}
+	if st.Code != nil {
+		p.Code = st.Code.String()
+	}
+
+	traces := st.GetTraces(e.TracesOpts...)
+	for i := range traces {
+		trace := &traces[i]
+		if len(trace.Stack) == 0 {
+			continue
+		}
+		stack := trace.Stack[len(trace.Stack)-1]
+		pe := ProblemError{
+			Type:     stack.Type.String(),
+			Code:     stack.Code.String(),
+			Severity: stack.Severity.String(),
+			Location: stack.Location.String(),
+			Message:  stack.Message,
+		}
+		if !e.Sanitize {
+			pe.Trace = trace.String()
+		}
+		if stack.Location != nil {
+			pe.Line, pe.Column = 1, 0
+			if stack.Position != nil {
+				pe.Line, pe.Column = stack.Position.Line, stack.Position.Column
+			}
+		}
+		p.Errors = append(p.Errors, pe)
+	}
+	return p
+}
+
+// status returns the HTTP status of the StackTrace.
+func (e *Encoder) status(st *stacktrace.StackTrace) int {
+	if e.Status == nil {
+		return DefaultStatusMapping().Resolve(st)
+	}
+	return e.Status(st)
+}
+
+// sanitize returns a copy of the StackTrace tree without the Info and the underlying errors.
+func sanitize(st *stacktrace.StackTrace) *stacktrace.StackTrace {
+	if st == nil {
+		return nil
+	}
+	result := *st
+	result.Info = stacktrace.StructInfo{}
+	result.Err = nil
+	result.Wrapped = sanitize(st.Wrapped)
+	result.List = nil
+	for _, elem := range st.List {
+		result.Append(sanitize(elem))
+	}
+	return &result
+}
+
+// Write writes the error as problem details, nothing is written for the nil error.
+func (e *Encoder) Write(w http.ResponseWriter, err error) error {
+	return WriteProblem(w, e.Problem(err))
+}
+
+// WriteProblem writes the problem details, nothing is written for the nil problem.
+func WriteProblem(w http.ResponseWriter, p *Problem) error {
+	if p == nil {
+		return nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encode problem: %w", err)
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, err = w.Write(data)
+	return err
+}
+
+// Write writes the error as problem details with the default encoder, nothing is written for the nil error.
+func Write(w http.ResponseWriter, err error) error {
+	return NewEncoder().Write(w, err)
+}
+
+// ErrNotProblem is returned by Decode for the responses which are not problem details.
+var ErrNotProblem = errors.New("httpx: response is not a problem details document")
+
+// DecodeProblem decodes the problem details from the reader.
+func DecodeProblem(r io.Reader) (*Problem, error) {
+	p := &Problem{}
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, fmt.Errorf("httpx: decode problem: %w", err)
+	}
+	return p, nil
+}
+
+// Decode decodes the problem details response into a StackTrace.
+// It returns ErrNotProblem if the response is not a problem details document.
+// The body of the response is not closed.
+func Decode(resp *http.Response) (*stacktrace.StackTrace, error) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != ContentType {
+		return nil, ErrNotProblem
+	}
+	p, err := DecodeProblem(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status == 0 {
+		p.Status = resp.StatusCode
+	}
+	return p.StackTrace(), nil
+}
+
+// StackTrace rebuilds the StackTrace of the problem details.
+// The problem type prefixed with DefaultTypeBase is used as the type, unless it is the code.
+func (p *Problem) StackTrace() *stacktrace.StackTrace {
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+	opts := make([]stacktrace.Option, 0)
+	if t, ok := strings.CutPrefix(p.Type, DefaultTypeBase); ok && t != p.Code {
+		opts = append(opts, stacktrace.WithType(stacktrace.Type(t)))
+	}
+	if p.Code != "" {
+		opts = append(opts, stacktrace.WithCode(stacktrace.Code(p.Code)))
+	}
+	if p.Status != 0 {
+		opts = append(opts, stacktrace.WithHTTPStatus(p.Status))
+	}
+	if p.Instance != "" {
+		opts = append(opts, stacktrace.WithInfo("instance", p.Instance))
+	}
+	st := stacktrace.New(msg, opts...)
+	for _, pe := range p.Errors {
+		opts := make([]stacktrace.Option, 0)
+		if pe.Type != "" {
+			opts = append(opts, stacktrace.WithType(stacktrace.Type(pe.Type)))
+		}
+		if pe.Code != "" {
+			opts = append(opts, stacktrace.WithCode(stacktrace.Code(pe.Code)))
+		}
+		if pe.Severity != "" {
+			opts = append(opts, stacktrace.WithSeverity(stacktrace.Severity(pe.Severity)))
+		}
+		if pe.Location != "" {
+			opts = append(opts,
+				stacktrace.WithLocation(pe.Location),
+				stacktrace.WithPosition(stacktrace.NewPosition(pe.Line, pe.Column)),
+			)
+		}
+		st.Append(stacktrace.New(pe.Message, opts...))
+	}
+	return st
+}
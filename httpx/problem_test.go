@@ -0,0 +1,213 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestEncoder_Problem(t *testing.T) {
+	validation := func() *stacktrace.StackTrace {
+		return stacktrace.New("invalid document", stacktrace.WithType("validation")).
+			Append(stacktrace.New("unknown property",
+				stacktrace.WithType("parsing"),
+				stacktrace.WithSeverity(stacktrace.SeverityError),
+				stacktrace.WithLocation("api.raml"),
+				stacktrace.WithPosition(stacktrace.NewPosition(3, 5)),
+			))
+	}
+	catalog := stacktrace.NewCatalog().Register(stacktrace.CatalogEntry{
+		Code:    "E1",
+		Title:   "Broken",
+		HelpURL: "https://example.com/E1",
+	})
+	tests := []struct {
+		name    string
+		encoder *Encoder
+		err     error
+		want    *Problem
+	}{
+		{
+			name:    "Test plain error",
+			encoder: NewEncoder(),
+			err:     errors.New("boom"),
+			want: &Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "boom",
+			},
+		},
+		{
+			name:    "Test without type",
+			encoder: NewEncoder(),
+			err:     stacktrace.New("boom"),
+			want: &Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "boom",
+				Errors: []ProblemError{{Message: "boom", Trace: "boom"}},
+			},
+		},
+		{
+			name: "Test validation",
+			encoder: &Encoder{
+				TypeBase: DefaultTypeBase,
+				Status:   StatusByType(map[stacktrace.Type]int{"validation": http.StatusUnprocessableEntity}, http.StatusInternalServerError),
+			},
+			err: validation(),
+			want: &Problem{
+				Type:   "urn:stacktrace:validation",
+				Title:  "validation",
+				Status: http.StatusUnprocessableEntity,
+				Detail: "invalid document",
+				Errors: []ProblemError{{
+					Type:     "parsing",
+					Severity: "error",
+					Location: "api.raml",
+					Line:     3,
+					Column:   5,
+					Message:  "unknown property",
+					Trace:    "error: parsing: api.raml:3:5: unknown property",
+				}},
+			},
+		},
+		{
+			name:    "Test catalog entry",
+			encoder: &Encoder{TypeBase: DefaultTypeBase, Catalog: catalog, Status: NewEncoder().Status},
+			err:     stacktrace.New("boom", catalog.WithCode("E1")),
+			want: &Problem{
+				Type:   "https://example.com/E1",
+				Title:  "Broken",
+				Status: http.StatusInternalServerError,
+				Detail: "boom",
+				Code:   "E1",
+				Errors: []ProblemError{{Code: "E1", Message: "boom", Trace: "[E1]: boom"}},
+			},
+		},
+		{
+			name:    "Test zero encoder",
+			encoder: &Encoder{},
+			err:     stacktrace.New("invalid document", stacktrace.WithType(TypeValidation)),
+			want: &Problem{
+				Type:   "validation",
+				Title:  "validation",
+				Status: http.StatusUnprocessableEntity,
+				Detail: "invalid document",
+				Errors: []ProblemError{{Type: "validation", Message: "invalid document", Trace: "validation: invalid document"}},
+			},
+		},
+		{
+			name: "Test redaction",
+			encoder: &Encoder{
+				TypeBase:   DefaultTypeBase,
+				TracesOpts: []stacktrace.TracesOpt{stacktrace.WithRedactionPolicy(stacktrace.DefaultRedactionPolicy())},
+			},
+			err: stacktrace.New("login failed", stacktrace.WithInfo("password", "hunter2")),
+			want: &Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "login failed: password: [REDACTED]",
+				Errors: []ProblemError{{Message: "login failed: password: [REDACTED]", Trace: "login failed: password: [REDACTED]"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.encoder.Problem(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Problem() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblem_JSON(t *testing.T) {
+	p := &Problem{
+		Type:       "about:blank",
+		Status:     http.StatusBadRequest,
+		Extensions: map[string]any{"traceId": "abc"},
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"status":400,"traceId":"abc","type":"about:blank"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+	got := &Problem{}
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, p)
+	}
+}
+
+func TestWriteAndDecode(t *testing.T) {
+	encoder := NewEncoder()
+	encoder.Status = StatusByType(map[stacktrace.Type]int{"validation": http.StatusUnprocessableEntity}, http.StatusInternalServerError)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := stacktrace.New("invalid document", stacktrace.WithType("validation")).
+			Append(stacktrace.New("unknown property",
+				stacktrace.WithType("parsing"),
+				stacktrace.WithLocation("api.raml"),
+				stacktrace.WithPosition(stacktrace.NewPosition(3, 5)),
+			))
+		_ = encoder.Write(w, err)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", resp.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if got := resp.Header.Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	st, err := Decode(resp)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	want := "validation: invalid document; parsing: api.raml:3:5: unknown property"
+	if got := st.Error(); got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+	if st.HTTPStatus != http.StatusUnprocessableEntity {
+		t.Errorf("HTTPStatus = %d, want %d", st.HTTPStatus, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestWrite_Nil(t *testing.T) {
+	if p := NewEncoder().Problem(nil); p != nil {
+		t.Errorf("Problem() = %+v, want nil", p)
+	}
+	rec := httptest.NewRecorder()
+	if err := Write(rec, nil); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "" {
+		t.Errorf("Write() = %q, want nothing written", rec.Body.String())
+	}
+}
+
+func TestDecode_NotProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "text/plain")
+	_, _ = rec.WriteString("boom")
+	resp := rec.Result()
+	defer resp.Body.Close()
+	if _, err := Decode(resp); !errors.Is(err, ErrNotProblem) {
+		t.Errorf("Decode() error = %v, want %v", err, ErrNotProblem)
+	}
+}
@@ -0,0 +1,135 @@
+package httpx
+
+import (
+	"net/http"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// Well-known types mapped by DefaultStatusMapping.
+const (
+	TypeValidation   stacktrace.Type = "validation"
+	TypeNotFound     stacktrace.Type = "not-found"
+	TypeConflict     stacktrace.Type = "conflict"
+	TypeUnauthorized stacktrace.Type = "unauthorized"
+	TypeForbidden    stacktrace.Type = "forbidden"
+)
+
+// StatusMapping maps the codes, the types and the severities of the StackTraces to the HTTP statuses.
+type StatusMapping struct {
+	// Codes maps the codes, they take priority over the types.
+	Codes map[stacktrace.Code]int
+	// Types maps the types, they take priority over the severities.
+	Types map[stacktrace.Type]int
+	// Severities maps the severities.
+	Severities map[stacktrace.Severity]int
+	// Default is the status of the StackTraces without a mapped status, defaults to 500.
+	Default int
+}
+
+// DefaultStatusMapping returns the mapping of the well-known types, e.g. validation to 422 and not-found to 404.
+// The other StackTraces are mapped to 500.
+func DefaultStatusMapping() *StatusMapping {
+	return &StatusMapping{
+		Types: map[stacktrace.Type]int{
+			TypeValidation:   http.StatusUnprocessableEntity,
+			TypeNotFound:     http.StatusNotFound,
+			TypeConflict:     http.StatusConflict,
+			TypeUnauthorized: http.StatusUnauthorized,
+			TypeForbidden:    http.StatusForbidden,
+		},
+		Default: http.StatusInternalServerError,
+	}
+}
+
+// status returns the status of the node itself, zero if none.
+func (m *StatusMapping) status(st *stacktrace.StackTrace) int {
+	if st.HTTPStatus != 0 {
+		return st.HTTPStatus
+	}
+	if st.Code != nil {
+		if status, ok := m.Codes[*st.Code]; ok {
+			return status
+		}
+	}
+	if st.Type != nil {
+		if status, ok := m.Types[*st.Type]; ok {
+			return status
+		}
+	}
+	if st.Severity != nil {
+		if status, ok := m.Severities[*st.Severity]; ok {
+			return status
+		}
+	}
+	return 0
+}
+
+// resolve returns the status of the root-most mapped nodes of the StackTrace.
+// The status of a node is its own one if it is mapped, else the combination of the statuses of its wrapped
+// StackTrace and its list. An unmapped node without them has the default status.
+func (m *StatusMapping) resolve(st *stacktrace.StackTrace) int {
+	if status := m.status(st); status != 0 {
+		return status
+	}
+	statuses := make([]int, 0, 1+len(st.List))
+	if st.Wrapped != nil {
+		statuses = append(statuses, m.resolve(st.Wrapped))
+	}
+	for _, elem := range st.List {
+		statuses = append(statuses, m.resolve(elem))
+	}
+	if len(statuses) == 0 {
+		return m.fallback()
+	}
+	return combineStatuses(statuses)
+}
+
+// fallback returns the status of the StackTraces without a mapped status.
+func (m *StatusMapping) fallback() int {
+	if m.Default != 0 {
+		return m.Default
+	}
+	return http.StatusInternalServerError
+}
+
+// combineStatuses returns the status of the given sibling statuses.
+// It is the common status if they are the same, else the common server error status if any,
+// 500 for different server error statuses and 400 for different client error statuses.
+func combineStatuses(statuses []int) int {
+	client, server := 0, 0
+	for _, status := range statuses {
+		target := &client
+		if status >= http.StatusInternalServerError {
+			target = &server
+		}
+		switch *target {
+		case 0:
+			*target = status
+		case status:
+		default:
+			*target = -1
+		}
+	}
+	switch {
+	case server > 0:
+		return server
+	case server < 0:
+		return http.StatusInternalServerError
+	case client > 0:
+		return client
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// Resolve returns the HTTP status of the StackTrace.
+// The status of a node is its own HTTP status set with WithHTTPStatus, else the status of its code, type or severity.
+// The status of the root-most mapped nodes is returned: the status of the StackTrace itself if it is mapped,
+// else the status of its wrapped StackTrace and its list, resolved the same way. An unmapped node without them
+// has the default status, so an unknown failure is not hidden by its siblings. The different statuses
+// of the siblings are combined: a server error outranks the client errors, the different server errors give 500
+// and the different client errors give 400.
+func (m *StatusMapping) Resolve(st *stacktrace.StackTrace) int {
+	return m.resolve(st)
+}
@@ -0,0 +1,87 @@
+package httpx
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestStatusMapping_Resolve(t *testing.T) {
+	mapping := DefaultStatusMapping()
+	mapping.Codes = map[stacktrace.Code]int{"RAML0404": http.StatusNotFound}
+	mapping.Severities = map[stacktrace.Severity]int{stacktrace.SeverityWarning: http.StatusBadRequest}
+	tests := []struct {
+		name string
+		st   *stacktrace.StackTrace
+		want int
+	}{
+		{
+			name: "Test default",
+			st:   stacktrace.New("boom"),
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "Test type",
+			st:   stacktrace.New("invalid", stacktrace.WithType(TypeValidation)),
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Test code before type",
+			st:   stacktrace.New("missing", stacktrace.WithType(TypeValidation), stacktrace.WithCode("RAML0404")),
+			want: http.StatusNotFound,
+		},
+		{
+			name: "Test severity",
+			st:   stacktrace.New("deprecated", stacktrace.WithSeverity(stacktrace.SeverityWarning)),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Test explicit root status",
+			st: stacktrace.New("gone", stacktrace.WithHTTPStatus(http.StatusGone)).
+				Append(stacktrace.New("invalid", stacktrace.WithType(TypeValidation))),
+			want: http.StatusGone,
+		},
+		{
+			name: "Test same nested statuses",
+			st: stacktrace.New("invalid document").
+				Append(stacktrace.New("unknown property", stacktrace.WithType(TypeValidation))).
+				Append(stacktrace.NewWrapped("include", stacktrace.New("invalid type", stacktrace.WithType(TypeValidation)))),
+			want: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Test different nested client errors",
+			st: stacktrace.New("invalid document").
+				Append(stacktrace.New("unknown property", stacktrace.WithType(TypeValidation))).
+				Append(stacktrace.NewWrapped("include", stacktrace.New("no file", stacktrace.WithType(TypeNotFound)))),
+			want: http.StatusBadRequest,
+		},
+		{
+			name: "Test unmapped sibling",
+			st: stacktrace.New("invalid document").
+				Append(stacktrace.New("unknown property", stacktrace.WithType(TypeValidation))).
+				Append(stacktrace.New("parser crashed")),
+			want: http.StatusInternalServerError,
+		},
+		{
+			name: "Test root-most mapped node",
+			st: stacktrace.New("unauthorized", stacktrace.WithType(TypeUnauthorized)).
+				Append(stacktrace.New("unknown property", stacktrace.WithType(TypeValidation))),
+			want: http.StatusUnauthorized,
+		},
+		{
+			name: "Test nested explicit status",
+			st: stacktrace.New("invalid document").
+				Append(stacktrace.New("unknown property", stacktrace.WithType(TypeValidation))).
+				Append(stacktrace.New("backend down", stacktrace.WithHTTPStatus(http.StatusBadGateway))),
+			want: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mapping.Resolve(tt.st); got != tt.want {
+				t.Errorf("Resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
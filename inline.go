@@ -0,0 +1,243 @@
+package stacktrace
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// InlineSuppressionDirective is the directive of the inline suppression comments, e.g.
+//
+//	baseUri: http://example.com # stacktrace:ignore unknown-property
+//
+// The comment suppresses the diagnostics located on its line, or on the line below it if the comment
+// is alone on its line, whose type or code is listed after the directive. A directive without a list suppresses all diagnostics.
+const InlineSuppressionDirective = "stacktrace:ignore"
+
+// TypeUnusedSuppression is the type of the warnings reporting the inline suppressions which did not match any diagnostic.
+const TypeUnusedSuppression Type = "unused-suppression"
+
+// inlineDirective is an inline suppression comment.
+type inlineDirective struct {
+	line   int
+	column int
+	names  []string
+	// standalone is set if the comment is alone on its line.
+	standalone bool
+	used       bool
+}
+
+// match checks if the directive suppresses the diagnostic with the given stack.
+func (d *inlineDirective) match(stack []Stack) bool {
+	if len(d.names) == 0 {
+		return true
+	}
+	for _, name := range d.names {
+		for i := range stack {
+			if stack[i].Type != nil && string(*stack[i].Type) == name || stack[i].Code != nil && string(*stack[i].Code) == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inlineFile contains the inline suppression comments of a source file.
+type inlineFile struct {
+	location   Location
+	directives map[int]*inlineDirective
+	// err is the error of the parsing, the directives before it are kept.
+	err error
+}
+
+// parseInlineDirectives returns the inline suppression comments of the source.
+// A comment is recognized from the last comment marker of its line followed by the directive,
+// so the quoted directives before the comment are skipped.
+func parseInlineDirectives(src []byte) (map[int]*inlineDirective, error) {
+	directives := make(map[int]*inlineDirective)
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	// the lines are not limited by the default token size
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(src)+1)
+	for line := 1; scanner.Scan(); line++ {
+		if d := parseInlineDirective(scanner.Text()); d != nil {
+			d.line = line
+			directives[line] = d
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return directives, fmt.Errorf("read inline suppressions: %w", err)
+	}
+	return directives, nil
+}
+
+// parseInlineDirective returns the inline suppression comment of the line, nil if there is none.
+func parseInlineDirective(text string) *inlineDirective {
+	for hash := strings.LastIndex(text, "#"); hash >= 0; hash = strings.LastIndex(text[:hash], "#") {
+		comment := strings.TrimLeft(text[hash+1:], " \t")
+		if !strings.HasPrefix(comment, InlineSuppressionDirective) {
+			continue
+		}
+		rest := comment[len(InlineSuppressionDirective):]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		if reason := strings.Index(rest, "--"); reason >= 0 {
+			rest = rest[:reason]
+		}
+		names := strings.FieldsFunc(rest, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		return &inlineDirective{
+			column:     hash + 1,
+			names:      names,
+			standalone: strings.TrimSpace(text[:hash]) == "",
+		}
+	}
+	return nil
+}
+
+// inlineSuppressions reads the inline suppression comments of the diagnostics locations.
+type inlineSuppressions struct {
+	fsys  fs.FS
+	opts  *TracesOptions
+	files map[Location]*inlineFile
+	order []*inlineFile
+}
+
+// file returns the inline suppression comments of the given location, nil if it cannot be read.
+func (s *inlineSuppressions) file(loc Location) *inlineFile {
+	if f, ok := s.files[loc]; ok {
+		return f
+	}
+	s.files[loc] = nil
+	name := path.Clean(strings.TrimPrefix(strings.ReplaceAll(string(loc), "\\", "/"), "/"))
+	if !fs.ValidPath(name) {
+		return nil
+	}
+	src, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return nil
+	}
+	directives, err := parseInlineDirectives(src)
+	f := &inlineFile{location: loc, directives: directives, err: err}
+	s.files[loc] = f
+	s.order = append(s.order, f)
+	return f
+}
+
+// suppressed checks if the trace is suppressed by an inline comment.
+// The diagnostic is located by the innermost stack entry with a location.
+func (s *inlineSuppressions) suppressed(trace Trace) bool {
+	for i := len(trace.Stack) - 1; i >= 0; i-- {
+		stack := trace.Stack[i]
+		if stack.Location == nil {
+			continue
+		}
+		f := s.file(*stack.Location)
+		if f == nil {
+			return false
+		}
+		line := 1
+		if stack.Position != nil && stack.Position.Line > 0 {
+			line = stack.Position.Line
+		}
+		if d, ok := f.directives[line]; ok && d.match(trace.Stack) {
+			d.used = true
+			return true
+		}
+		if d, ok := f.directives[line-1]; ok && d.standalone && d.match(trace.Stack) {
+			d.used = true
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// unused returns the warnings of the inline suppressions which did not match any diagnostic
+// and of the source files whose inline suppressions could not be read.
+func (s *inlineSuppressions) unused() []Trace {
+	// the warnings are not deduplicated with the diagnostics
+	opts := *s.opts
+	opts.dupLocs = make(map[string]struct{})
+	traces := make([]Trace, 0)
+	for _, f := range s.order {
+		if f.err != nil {
+			st := New(f.err.Error(),
+				WithSeverity(SeverityWarning),
+				WithType(TypeUnusedSuppression),
+				WithLocation(string(f.location)),
+			)
+			traces = append(traces, st.getTraces(&opts)...)
+		}
+		lines := make([]int, 0, len(f.directives))
+		for line, d := range f.directives {
+			if !d.used {
+				lines = append(lines, line)
+			}
+		}
+		sort.Ints(lines)
+		for _, line := range lines {
+			d := f.directives[line]
+			stOpts := []Option{
+				WithSeverity(SeverityWarning),
+				WithType(TypeUnusedSuppression),
+				WithLocation(string(f.location)),
+				WithPosition(NewPosition(d.line, d.column)),
+			}
+			st := New("unused suppression", stOpts...)
+			if len(d.names) > 0 {
+				st = NewT("unused suppression of {names}", Args{"names": strings.Join(d.names, ", ")}, stOpts...)
+			}
+			traces = append(traces, st.getTraces(&opts)...)
+		}
+	}
+	return traces
+}
+
+// ApplyInlineSuppressions returns the traces without the ones suppressed by the inline comments
+// of their source files read from fsys. The locations of the traces are used as the paths in fsys,
+// the leading slash of the absolute locations is removed. The warnings of the inline suppressions
+// which did not match any diagnostic are appended to the result, they are built with the given options,
+// e.g. WithLocalizer.
+func ApplyInlineSuppressions(fsys fs.FS, traces []Trace, opts ...TracesOpt) []Trace {
+	o := NewTracesOptions()
+	for _, opt := range opts {
+		opt.Apply(o)
+	}
+	return applyInlineSuppressions(fsys, traces, o)
+}
+
+// applyInlineSuppressions applies the inline suppressions with the given traces options.
+func applyInlineSuppressions(fsys fs.FS, traces []Trace, opts *TracesOptions) []Trace {
+	s := &inlineSuppressions{
+		fsys:  fsys,
+		opts:  opts,
+		files: make(map[Location]*inlineFile),
+	}
+	kept := make([]Trace, 0, len(traces))
+	for _, trace := range traces {
+		if !s.suppressed(trace) {
+			kept = append(kept, trace)
+		}
+	}
+	return append(kept, s.unused()...)
+}
+
+type inlineSuppressionsOpt struct {
+	fsys fs.FS
+}
+
+func (o inlineSuppressionsOpt) Apply(opts *TracesOptions) {
+	opts.InlineSuppressions = o.fsys
+}
+
+// WithInlineSuppressions applies the inline suppression comments of the source files read from fsys to the traces.
+// See ApplyInlineSuppressions.
+func WithInlineSuppressions(fsys fs.FS) TracesOpt {
+	return inlineSuppressionsOpt{fsys: fsys}
+}
@@ -0,0 +1,81 @@
+package stacktrace
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStackTrace_GetTracesWithInlineSuppressions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/api.raml": &fstest.MapFile{Data: []byte(`#%RAML 1.0
+title: API
+baseUri: http://example.com # stacktrace:ignore unknown-property
+# stacktrace:ignore RAML0001 -- generated file
+version: 1
+mediaType: json # stacktrace:ignore
+description: text
+protocols: [HTTP] # stacktrace:ignore deprecated, unknown-property
+`)},
+	}
+	st := New("validation failed").
+		Append(New("property is not allowed", WithType("unknown-property"), WithLocation("/specs/api.raml"), WithPosition(NewPosition(3, 1)))).
+		Append(New("invalid version", WithType("parsing"), WithCode("RAML0001"), WithLocation("/specs/api.raml"), WithPosition(NewPosition(5, 10)))).
+		Append(New("invalid media type", WithType("validating"), WithLocation("/specs/api.raml"), WithPosition(NewPosition(6, 1)))).
+		Append(New("trailing comment does not apply below", WithType("validating"), WithLocation("/specs/api.raml"), WithPosition(NewPosition(7, 1)))).
+		Append(New("file is not readable", WithType("unknown-property"), WithLocation("/specs/missing.raml"), WithPosition(NewPosition(3, 1))))
+
+	traces := st.GetTraces(WithInlineSuppressions(fsys))
+	got := make([]string, 0, len(traces))
+	for _, trace := range traces {
+		stack := trace.Stack[len(trace.Stack)-1]
+		got = append(got, stack.Severity.String()+" "+*stack.LinePos+" "+stack.Message)
+	}
+	want := []string{
+		" /specs/api.raml:7:1 trailing comment does not apply below",
+		" /specs/missing.raml:3:1 file is not readable",
+		"warning /specs/api.raml:8:19 unused suppression of deprecated, unknown-property",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTraces() = %v, want %v", got, want)
+	}
+}
+
+func TestStackTrace_GetTracesWithInlineSuppressionsLocalized(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api.raml": &fstest.MapFile{Data: []byte("title: API # stacktrace:ignore parsing\n")},
+	}
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{
+		"unused suppression of {names}": "unbenutzte Unterdrückung von {names}",
+	}}
+	st := New("invalid title", WithType("validating"), WithLocation("api.raml"), WithPosition(NewPosition(2, 1)))
+	traces := st.GetTraces(WithInlineSuppressions(fsys), WithLocalizer(bundle))
+	if len(traces) != 2 || traces[1].Stack[0].Message != "unbenutzte Unterdrückung von parsing" {
+		t.Errorf("GetTraces() = %v", traces)
+	}
+}
+
+func TestParseInlineDirectives(t *testing.T) {
+	src := []byte(`a: b # stacktrace:ignore one,two
+# stacktrace:ignored
+c: "stacktrace:ignore"
+  #stacktrace:ignore
+d: "# stacktrace:ignore quoted" # stacktrace:ignore three -- see #12
+e: ` + strings.Repeat("x", 100*1024) + `
+f: g # stacktrace:ignore four
+`)
+	got, err := parseInlineDirectives(src)
+	if err != nil {
+		t.Fatalf("parseInlineDirectives() error = %v", err)
+	}
+	want := map[int]*inlineDirective{
+		1: {line: 1, column: 6, names: []string{"one", "two"}},
+		4: {line: 4, column: 3, names: []string{}, standalone: true},
+		5: {line: 5, column: 33, names: []string{"three"}},
+		7: {line: 7, column: 6, names: []string{"four"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseInlineDirectives() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Localizer translates the messages of the errors.
+type Localizer interface {
+	// Localize returns the translated message of the error with the given code and message template.
+	// The template is the message itself for the errors without a template.
+	// It returns false if there is no translation.
+	Localize(code Code, template string, args Args) (string, bool)
+}
+
+// Bundle contains the translated message templates of one locale.
+type Bundle struct {
+	// Locale is the locale of the bundle, e.g. "de" or "de-CH".
+	Locale string
+	// Messages maps the error codes and the English message templates to the translated message templates.
+	Messages map[string]string
+}
+
+// Localize implements the Localizer interface.
+// The error code has priority over the message template.
+func (b *Bundle) Localize(code Code, template string, args Args) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	if code != "" {
+		if tr, ok := b.Messages[string(code)]; ok {
+			return RenderTemplate(tr, args), true
+		}
+	}
+	if template != "" {
+		if tr, ok := b.Messages[template]; ok {
+			return RenderTemplate(tr, args), true
+		}
+	}
+	return "", false
+}
+
+// Bundles is a set of bundles by locale.
+type Bundles map[string]*Bundle
+
+// Localizer returns the localizer of the given locale.
+// It falls back to the base language, e.g. "de" for "de-CH", and returns nil if there is no bundle.
+func (b Bundles) Localizer(locale string) Localizer {
+	for locale != "" {
+		if bundle, ok := b[locale]; ok {
+			return bundle
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return nil
+}
+
+// LoadBundles loads the JSON bundles from the files of fsys matching the pattern.
+// The locale is the file name without the extension, e.g. "locales/de.json" is the "de" bundle.
+// Each file is a JSON object mapping the error codes and the message templates to the translations.
+func LoadBundles(fsys fs.FS, pattern string) (Bundles, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	bundles := make(Bundles, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read bundle: %w", err)
+		}
+		bundle := &Bundle{
+			Locale: strings.TrimSuffix(path.Base(name), path.Ext(name)),
+		}
+		if err := json.Unmarshal(data, &bundle.Messages); err != nil {
+			return nil, fmt.Errorf("decode bundle %s: %w", name, err)
+		}
+		bundles[bundle.Locale] = bundle
+	}
+	return bundles, nil
+}
+
+// LocalizedMessage returns the message of the StackTrace translated by the given localizer.
+// It returns the original message if the localizer is nil or has no translation.
+func (st *StackTrace) LocalizedMessage(l Localizer) string {
+	return st.localizedMessage(l, currentRedactionPolicy())
+}
+
+// localizedMessage returns the message of the StackTrace translated by the given localizer.
+// The template arguments are redacted by the given policy before rendering.
+func (st *StackTrace) localizedMessage(l Localizer, p *RedactionPolicy) string {
+	args := p.RedactArgs(st.Args)
+	if l == nil {
+		if p != nil && st.Template != "" {
+			return RenderTemplate(st.Template, args)
+		}
+		return st.Message
+	}
+	var code Code
+	if st.Code != nil {
+		code = *st.Code
+	}
+	template := st.Template
+	if template == "" {
+		template = st.Message
+	}
+	if msg, ok := l.Localize(code, template, args); ok {
+		return msg
+	}
+	return st.localizedMessage(nil, p)
+}
+
+type localizerOpt struct {
+	localizer Localizer
+}
+
+func (o localizerOpt) Apply(opts *TracesOptions) {
+	opts.Localizer = o.localizer
+}
+
+// WithLocalizer sets the localizer of the traces messages.
+// The localization is done by the traces only, the Error and String methods render the original messages.
+func WithLocalizer(l Localizer) TracesOpt {
+	return localizerOpt{localizer: l}
+}
@@ -0,0 +1,119 @@
+package stacktrace
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundles_Localizer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/de.json": &fstest.MapFile{Data: []byte(`{
+			"RAML1023": "Eigenschaft {name} ist nicht erlaubt",
+			"unexpected end of file": "unerwartetes Dateiende"
+		}`)},
+		"locales/fr.json": &fstest.MapFile{Data: []byte(`{
+			"property {name} is not allowed": "la propriété {name} n'est pas autorisée"
+		}`)},
+	}
+	bundles, err := LoadBundles(fsys, "locales/*.json")
+	if err != nil {
+		t.Fatalf("LoadBundles() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		st     *StackTrace
+		want   string
+	}{
+		{
+			name:   "Check translation by code",
+			locale: "de-CH",
+			st:     NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithCode("RAML1023")),
+			want:   "[RAML1023]: Eigenschaft baseUri ist nicht erlaubt",
+		},
+		{
+			name:   "Check translation by template",
+			locale: "fr",
+			st:     NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithCode("RAML1023")),
+			want:   "[RAML1023]: la propriété baseUri n'est pas autorisée",
+		},
+		{
+			name:   "Check translation by plain message",
+			locale: "de",
+			st:     New("unexpected end of file", WithLocation("/tmp/api.raml")),
+			want:   "/tmp/api.raml:1: unerwartetes Dateiende",
+		},
+		{
+			name:   "Check fallback to english",
+			locale: "de",
+			st:     New("unknown message", WithInfo("key", "value")),
+			want:   "unknown message: key: value",
+		},
+		{
+			name:   "Check no localizer",
+			locale: "",
+			st:     New("unexpected end of file"),
+			want:   "unexpected end of file",
+		},
+		{
+			name:   "Check missing locale",
+			locale: "ja",
+			st:     NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithCode("RAML1023")),
+			want:   "[RAML1023]: property baseUri is not allowed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := tt.st.GetTraces(WithLocalizer(bundles.Localizer(tt.locale)))
+			if len(traces) != 1 {
+				t.Fatalf("GetTraces() = %v, want 1 trace", traces)
+			}
+			if got := traces[0].String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStackTrace_GetTracesWithLocalizer(t *testing.T) {
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{"RAML1023": "Eigenschaft {name} ist nicht erlaubt"}}
+	st := NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithCode("RAML1023"))
+	traces := st.GetTraces(WithLocalizer(bundle))
+	if len(traces) != 1 || traces[0].Stack[0].Message != "Eigenschaft baseUri ist nicht erlaubt" {
+		t.Errorf("GetTraces() = %v", traces)
+	}
+	// the rendering of the error does not depend on the localizers of the traces
+	if got := st.String(); got != "[RAML1023]: property baseUri is not allowed" {
+		t.Errorf("String() = %v", got)
+	}
+	if got := st.LocalizedMessage(bundle); got != "Eigenschaft baseUri ist nicht erlaubt" {
+		t.Errorf("LocalizedMessage() = %v", got)
+	}
+	if got := st.LocalizedMessage(nil); got != "property baseUri is not allowed" {
+		t.Errorf("LocalizedMessage() = %v", got)
+	}
+}
+
+func TestStackTrace_StringL(t *testing.T) {
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{
+		"RAML1023":          "Eigenschaft {name} ist nicht erlaubt",
+		"validation failed": "Validierung fehlgeschlagen",
+	}}
+	st := New("validation failed").
+		Wrap(NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithCode("RAML1023"), WithInfo("file", "api.raml"))).
+		Append(New("unknown error"))
+
+	if got, want := st.StringL(bundle), "Validierung fehlgeschlagen: [RAML1023]: Eigenschaft baseUri ist nicht erlaubt: file: api.raml; unknown error"; got != want {
+		t.Errorf("StringL() = %v, want %v", got, want)
+	}
+	if got, want := st.Wrapped.OrigStringL(bundle), "[RAML1023]: Eigenschaft baseUri ist nicht erlaubt: file: api.raml"; got != want {
+		t.Errorf("OrigStringL() = %v, want %v", got, want)
+	}
+	if got, want := st.StringL(nil), st.String(); got != want {
+		t.Errorf("StringL(nil) = %v, want %v", got, want)
+	}
+	if got, want := st.Error(), "validation failed: [RAML1023]: property baseUri is not allowed: file: api.raml; unknown error"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+}
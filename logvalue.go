@@ -0,0 +1,55 @@
+//go:build go1.21
+
+package stacktrace
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// LogValue implements the slog.LogValuer interface.
+// The StackTrace is expanded into a group of its type, code, severity, position, message and info,
+// and the groups of the wrapped StackTrace and of the list. The values are redacted by the policy
+// set by SetRedactionPolicy.
+func (st *StackTrace) LogValue() slog.Value {
+	if st == nil {
+		return slog.StringValue("nil stacktrace")
+	}
+	return slog.GroupValue(st.logAttrs(currentRedactionPolicy())...)
+}
+
+// logAttrs returns the attributes of the StackTrace.
+func (st *StackTrace) logAttrs(p *RedactionPolicy) []slog.Attr {
+	attrs := make([]slog.Attr, 0)
+	if st.Type != nil {
+		attrs = append(attrs, slog.String("type", st.Type.String()))
+	}
+	if st.Code != nil {
+		attrs = append(attrs, slog.String("code", st.Code.String()))
+	}
+	if st.Severity != nil {
+		attrs = append(attrs, slog.String("severity", st.Severity.String()))
+	}
+	if pos := st.GetLocWithPosPtr(); pos != nil {
+		attrs = append(attrs, slog.String("position", *pos))
+	}
+	attrs = append(attrs, slog.String("message", p.RedactString(st.localizedMessage(nil, p))))
+	if keys := st.Info.SortedKeys(); len(keys) > 0 {
+		info := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			info = append(info, slog.String(k, p.RedactValue(k, st.Info.Get(k))))
+		}
+		attrs = append(attrs, slog.Attr{Key: "info", Value: slog.GroupValue(info...)})
+	}
+	if st.Wrapped != nil {
+		attrs = append(attrs, slog.Attr{Key: "wrapped", Value: slog.GroupValue(st.Wrapped.logAttrs(p)...)})
+	}
+	if len(st.List) > 0 {
+		list := make([]slog.Attr, 0, len(st.List))
+		for i, elem := range st.List {
+			list = append(list, slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(elem.logAttrs(p)...)})
+		}
+		attrs = append(attrs, slog.Attr{Key: "list", Value: slog.GroupValue(list...)})
+	}
+	return attrs
+}
@@ -0,0 +1,65 @@
+//go:build go1.21
+
+package stacktrace
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStackTrace_LogValue(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Test simple",
+			err: New("error message",
+				WithType("parsing"),
+				WithSeverity(SeverityError),
+				WithLocation("location.raml"),
+				WithPosition(NewPosition(3, 5)),
+			),
+			want: `{"msg":"failed","err":{"type":"parsing","severity":"error","position":"location.raml:3:5","message":"error message"}}`,
+		},
+		{
+			name: "Test info",
+			err:  New("error message", WithInfo("property", "baseUri"), WithInfo("token", NewSecret("abc"))),
+			want: `{"msg":"failed","err":{"message":"error message","info":{"property":"baseUri","token":"[REDACTED]"}}}`,
+		},
+		{
+			name: "Test children",
+			err: New("invalid document", WithCode("RAML0001")).
+				Wrap(New("inner")).
+				Append(New("first", WithLocation("a.raml"))).
+				Append(New("second")),
+			want: `{"msg":"failed","err":{"code":"RAML0001","message":"invalid document","wrapped":{"message":"inner"},` +
+				`"list":{"0":{"position":"a.raml:1","message":"first"},"1":{"message":"second"}}}}`,
+		},
+		{
+			name: "Test nil",
+			err:  (*StackTrace)(nil),
+			want: `{"msg":"failed","err":"nil stacktrace"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			logger.Info("failed", slog.Any("err", tt.err))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("LogValue() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
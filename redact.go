@@ -0,0 +1,196 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// Redacted is the default replacement of the redacted values.
+const Redacted = "[REDACTED]"
+
+// Secret wraps a sensitive value.
+// It is always rendered as Redacted, the raw value is available with Value.
+type Secret struct {
+	value any
+}
+
+// NewSecret wraps the given sensitive value.
+func NewSecret(value any) *Secret {
+	return &Secret{value: value}
+}
+
+// Value returns the raw value.
+func (s *Secret) Value() any {
+	if s == nil {
+		return nil
+	}
+	return s.value
+}
+
+// String implements the fmt.Stringer interface.
+func (s *Secret) String() string {
+	return Redacted
+}
+
+// GoString implements the fmt.GoStringer interface.
+func (s *Secret) GoString() string {
+	return Redacted
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s *Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// RedactionPolicy describes the sensitive data to redact when the errors are rendered.
+// Secret values are always redacted. The nil policy redacts only Secret values.
+type RedactionPolicy struct {
+	// Keys match the Info keys and the template argument names whose values are redacted.
+	Keys []*regexp.Regexp
+	// Values match the parts of the values and the messages to redact.
+	Values []*regexp.Regexp
+	// Replacement replaces the redacted data, defaults to Redacted.
+	Replacement string
+}
+
+// NewRedactionPolicy creates a new redaction policy from the given key and value regular expressions.
+// The key expressions are case-insensitive.
+func NewRedactionPolicy(keys, values []string) (*RedactionPolicy, error) {
+	p := &RedactionPolicy{
+		Keys:   make([]*regexp.Regexp, 0, len(keys)),
+		Values: make([]*regexp.Regexp, 0, len(values)),
+	}
+	for _, key := range keys {
+		re, err := regexp.Compile("(?i)" + key)
+		if err != nil {
+			return nil, fmt.Errorf("key pattern: %w", err)
+		}
+		p.Keys = append(p.Keys, re)
+	}
+	for _, value := range values {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("value pattern: %w", err)
+		}
+		p.Values = append(p.Values, re)
+	}
+	return p, nil
+}
+
+// DefaultRedactionPolicy returns a policy redacting the usual credential keys and bearer tokens.
+func DefaultRedactionPolicy() *RedactionPolicy {
+	p, _ := NewRedactionPolicy(
+		[]string{`passw(or)?d`, `secret`, `token`, `authorization`, `api[-_]?key`, `cookie`},
+		[]string{`(?i)bearer\s+[a-z0-9._~+/-]+=*`},
+	)
+	return p
+}
+
+// replacement returns the replacement of the redacted data.
+func (p *RedactionPolicy) replacement() string {
+	if p == nil || p.Replacement == "" {
+		return Redacted
+	}
+	return p.Replacement
+}
+
+// sensitiveKey checks if the values of the given key are sensitive.
+func (p *RedactionPolicy) sensitiveKey(key string) bool {
+	if p == nil {
+		return false
+	}
+	for _, re := range p.Keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactString replaces the parts of the string matching the value patterns.
+func (p *RedactionPolicy) RedactString(s string) string {
+	if p == nil {
+		return s
+	}
+	for _, re := range p.Values {
+		s = re.ReplaceAllLiteralString(s, p.replacement())
+	}
+	return s
+}
+
+// RedactValue returns the redacted string representation of the value of the given key.
+func (p *RedactionPolicy) RedactValue(key string, value any) string {
+	if isSecret(value) || p.sensitiveKey(key) {
+		return p.replacement()
+	}
+	return p.RedactString(Stringer(value).String())
+}
+
+// RedactArgs returns the template arguments with the sensitive values replaced.
+// The values matching the value patterns, e.g. the fmt.Stringer or the structs, are replaced
+// with their redacted string representation. The arguments are returned as is if nothing is redacted.
+func (p *RedactionPolicy) RedactArgs(args Args) Args {
+	var result Args
+	for name, value := range args {
+		var redacted any
+		if isSecret(value) || p.sensitiveKey(name) {
+			redacted = p.replacement()
+		} else if s := Stringer(value).String(); p.RedactString(s) != s {
+			redacted = p.RedactString(s)
+		} else {
+			continue
+		}
+		if result == nil {
+			result = make(Args, len(args))
+			for k, v := range args {
+				result[k] = v
+			}
+		}
+		result[name] = redacted
+	}
+	if result == nil {
+		return args
+	}
+	return result
+}
+
+// isSecret checks if the value is a Secret.
+func isSecret(value any) bool {
+	_, ok := value.(*Secret)
+	return ok
+}
+
+var (
+	redactionMu     sync.RWMutex
+	redactionPolicy *RedactionPolicy
+)
+
+// SetRedactionPolicy sets the redaction policy used to render all errors.
+// The nil policy redacts only Secret values.
+func SetRedactionPolicy(p *RedactionPolicy) {
+	redactionMu.Lock()
+	defer redactionMu.Unlock()
+	redactionPolicy = p
+}
+
+// currentRedactionPolicy returns the policy set by SetRedactionPolicy.
+func currentRedactionPolicy() *RedactionPolicy {
+	redactionMu.RLock()
+	defer redactionMu.RUnlock()
+	return redactionPolicy
+}
+
+type redactionPolicyOpt struct {
+	policy *RedactionPolicy
+}
+
+func (o redactionPolicyOpt) Apply(opts *TracesOptions) {
+	opts.Redaction = o.policy
+}
+
+// WithRedactionPolicy sets the redaction policy of the traces instead of the one set by SetRedactionPolicy.
+func WithRedactionPolicy(p *RedactionPolicy) TracesOpt {
+	return redactionPolicyOpt{policy: p}
+}
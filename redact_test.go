@@ -0,0 +1,139 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRedactionPolicy(t *testing.T) {
+	policy, err := NewRedactionPolicy([]string{"token", "^body$"}, []string{`sk_[a-z0-9]+`})
+	if err != nil {
+		t.Fatalf("NewRedactionPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy *RedactionPolicy
+		st     *StackTrace
+		want   string
+	}{
+		{
+			name:   "Check secret without policy",
+			policy: nil,
+			st:     New("request failed", WithInfo("auth", NewSecret("p4ss")), WithInfo("user", "alice")),
+			want:   "request failed: auth: [REDACTED]: user: alice",
+		},
+		{
+			name:   "Check key patterns",
+			policy: policy,
+			st:     New("request failed", WithInfo("X-Token", "abc"), WithInfo("Body", "{}"), WithInfo("bodySize", 2)),
+			want:   "request failed: Body: [REDACTED]: X-Token: [REDACTED]: bodySize: 2",
+		},
+		{
+			name:   "Check value patterns in message and info",
+			policy: policy,
+			st:     New("invalid key sk_live42", WithInfo("key", "sk_test1 and more")),
+			want:   "invalid key [REDACTED]: key: [REDACTED] and more",
+		},
+		{
+			name:   "Check template arguments",
+			policy: policy,
+			st:     NewT("token {token} of {user} is expired", Args{"token": "abc", "user": "alice"}),
+			want:   "token [REDACTED] of alice is expired",
+		},
+		{
+			name:   "Check wrapped",
+			policy: policy,
+			st:     NewWrapped("call failed", New("denied", WithInfo("token", "abc"))),
+			want:   "call failed: denied: token: [REDACTED]",
+		},
+		{
+			name:   "Check non-string template arguments",
+			policy: policy,
+			st: NewT("{key} of {data} is invalid", Args{
+				"key":  errors.New("key sk_live42"),
+				"data": struct{ Key string }{Key: "sk_test1"},
+			}),
+			want: "key [REDACTED] of {[REDACTED]} is invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRedactionPolicy(tt.policy)
+			defer SetRedactionPolicy(nil)
+			if got := tt.st.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnwrapWithRedactionPolicy(t *testing.T) {
+	policy, err := NewRedactionPolicy(nil, []string{`sk_[a-z0-9]+`})
+	if err != nil {
+		t.Fatalf("NewRedactionPolicy() error = %v", err)
+	}
+	SetRedactionPolicy(policy)
+	defer SetRedactionPolicy(nil)
+
+	st, ok := Unwrap(fmt.Errorf("auth: %w", New("bad key sk_abc")))
+	if !ok {
+		t.Fatalf("Unwrap() ok = false")
+	}
+	if st.Message != "auth" || st.Wrapped.Message != "bad key sk_abc" {
+		t.Errorf("Unwrap() messages = %q, %q, want %q, %q", st.Message, st.Wrapped.Message, "auth", "bad key sk_abc")
+	}
+	if got := st.String(); got != "auth: bad key [REDACTED]" {
+		t.Errorf("String() = %v, want %v", got, "auth: bad key [REDACTED]")
+	}
+}
+
+func TestStackTrace_RenderMessage(t *testing.T) {
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{"login of {user} failed": "Anmeldung von {user} fehlgeschlagen"}}
+	st := NewT("login of {user} failed", Args{"user": "bob"}, WithInfo("password", "hunter2"))
+	if got, want := st.RenderMessage(), "login of bob failed: password: hunter2"; got != want {
+		t.Errorf("RenderMessage() = %v, want %v", got, want)
+	}
+	got := st.RenderMessage(WithLocalizer(bundle), WithRedactionPolicy(DefaultRedactionPolicy()))
+	if want := "Anmeldung von bob fehlgeschlagen: password: [REDACTED]"; got != want {
+		t.Errorf("RenderMessage() = %v, want %v", got, want)
+	}
+}
+
+func TestSecret(t *testing.T) {
+	secret := NewSecret("p4ss")
+	st := New("message", WithInfo("password", secret))
+	if got := st.Info.Get("password").(*Secret).Value(); got != "p4ss" {
+		t.Errorf("Value() = %v, want p4ss", got)
+	}
+	if got := fmt.Sprintf("%v %s %#v", secret, secret, secret); got != "[REDACTED] [REDACTED] [REDACTED]" {
+		t.Errorf("Sprintf() = %v", got)
+	}
+	if got, _ := json.Marshal(map[string]any{"password": secret}); string(got) != `{"password":"[REDACTED]"}` {
+		t.Errorf("json.Marshal() = %s", got)
+	}
+}
+
+func TestStackTrace_GetTracesWithRedactionPolicy(t *testing.T) {
+	policy := DefaultRedactionPolicy()
+	st := NewT("login of {user} failed", Args{"user": "alice", "password": "p4ss"}, WithInfo("authorization", "Bearer abc.def"))
+	traces := st.GetTraces(WithRedactionPolicy(policy))
+	want := Stack{
+		Message:  "login of alice failed: authorization: [REDACTED]",
+		Text:     "login of alice failed",
+		Template: "login of {user} failed",
+		Args:     Args{"user": "alice", "password": "[REDACTED]"},
+		Info:     map[string]string{"authorization": "[REDACTED]"},
+
+		rawMessage: "login of alice failed",
+	}
+	if len(traces) != 1 || !reflect.DeepEqual(traces[0].Stack[0], want) {
+		t.Errorf("GetTraces() = %v, want %v", traces, want)
+	}
+	if st.Args["password"] != "p4ss" {
+		t.Errorf("GetTraces() must not modify the arguments, got %v", st.Args)
+	}
+}
@@ -0,0 +1,34 @@
+package stacktrace
+
+import "fmt"
+
+// Sentinel is an error matching every StackTrace with the same code.
+// It is intended to be used as the target of errors.Is.
+type Sentinel struct {
+	code Code
+}
+
+// NewSentinel creates a new sentinel for the given code.
+func NewSentinel(code Code) *Sentinel {
+	return &Sentinel{code: code}
+}
+
+// Code returns the code matched by the sentinel.
+func (s *Sentinel) Code() Code {
+	return s.code
+}
+
+// Error implements the error interface.
+func (s *Sentinel) Error() string {
+	return fmt.Sprintf("stacktrace: error code %s", s.code)
+}
+
+// match checks if the StackTrace or any StackTrace wrapped by it has the sentinel code.
+func (s *Sentinel) match(st *StackTrace) bool {
+	for ; st != nil; st = st.Wrapped {
+		if st.Code != nil && *st.Code == s.code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package stacktrace
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSentinel_Is(t *testing.T) {
+	sentinel := NewSentinel("RAML1023")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "Check same code",
+			err:  New("message", WithCode("RAML1023")),
+			want: true,
+		},
+		{
+			name: "Check wrapped code",
+			err:  NewWrapped("context", New("message", WithCode("RAML1023"))),
+			want: true,
+		},
+		{
+			name: "Check go wrapped stacktrace",
+			err:  fmt.Errorf("context: %w", New("message", WithCode("RAML1023"))),
+			want: true,
+		},
+		{
+			name: "Check other code",
+			err:  New("message", WithCode("RAML0001")),
+			want: false,
+		},
+		{
+			name: "Check without code",
+			err:  New("message"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, sentinel); got != tt.want {
+				t.Errorf("errors.Is() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+)
+
+// Known severities.
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// SeverityRank returns the rank of the severity, the higher the more severe.
+// The unknown severities rank as errors.
+func SeverityRank(s Severity) int {
+	switch s {
+	case SeverityInfo:
+		return 1
+	case SeverityWarning:
+		return 2
+	case SeverityCritical:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// SeverityRule rewrites the severity of the StackTraces matching all its non-empty conditions.
+type SeverityRule struct {
+	// Severity matches the current severity.
+	Severity Severity `json:"severity,omitempty"`
+	// Type matches the type.
+	Type Type `json:"type,omitempty"`
+	// Code matches the code.
+	Code Code `json:"code,omitempty"`
+	// Location is a glob matching the location, see SuppressionRule.Location.
+	Location string `json:"location,omitempty"`
+	// To is the new severity.
+	To Severity `json:"to"`
+
+	location *regexp.Regexp
+}
+
+// compile compiles the patterns of the rule.
+func (r *SeverityRule) compile() error {
+	if r.To == "" {
+		return errors.New("rule has no target severity")
+	}
+	if r.Location != "" {
+		re, err := compileGlob(r.Location)
+		if err != nil {
+			return fmt.Errorf("location: %w", err)
+		}
+		r.location = re
+	}
+	return nil
+}
+
+// match checks if the StackTrace matches all conditions of the rule.
+func (r *SeverityRule) match(st *StackTrace) bool {
+	if r.Severity != "" && (st.Severity == nil || *st.Severity != r.Severity) {
+		return false
+	}
+	if r.Type != "" && (st.Type == nil || *st.Type != r.Type) {
+		return false
+	}
+	if r.Code != "" && (st.Code == nil || *st.Code != r.Code) {
+		return false
+	}
+	if r.location != nil && (st.Location == nil || !r.location.MatchString(st.Location.String())) {
+		return false
+	}
+	return true
+}
+
+// WarningsAsErrors returns the rule turning all warnings into errors.
+func WarningsAsErrors() SeverityRule {
+	return SeverityRule{Severity: SeverityWarning, To: SeverityError}
+}
+
+// SeverityPolicy rewrites the severities of the StackTraces.
+// The first matching rule is applied to every StackTrace.
+type SeverityPolicy struct {
+	rules []*SeverityRule
+}
+
+// NewSeverityPolicy creates a new severity policy of the given rules.
+func NewSeverityPolicy(rules ...SeverityRule) (*SeverityPolicy, error) {
+	p := &SeverityPolicy{
+		rules: make([]*SeverityRule, 0, len(rules)),
+	}
+	for i := range rules {
+		rule := rules[i]
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("severity rule %d: %w", i, err)
+		}
+		p.rules = append(p.rules, &rule)
+	}
+	return p, nil
+}
+
+// LoadSeverityPolicy loads the severity policy from the JSON file with the given name from fsys.
+// The file is a JSON array of severity rules.
+func LoadSeverityPolicy(fsys fs.FS, name string) (*SeverityPolicy, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("read severity policy: %w", err)
+	}
+	var rules []SeverityRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("decode severity policy %s: %w", name, err)
+	}
+	return NewSeverityPolicy(rules...)
+}
+
+// rewrite returns the new severity of the StackTrace.
+func (p *SeverityPolicy) rewrite(st *StackTrace) (Severity, bool) {
+	if p == nil {
+		return "", false
+	}
+	for _, rule := range p.rules {
+		if rule.match(st) {
+			return rule.To, true
+		}
+	}
+	return "", false
+}
+
+// Apply returns a copy of the StackTrace with the severities of the StackTrace, the wrapped StackTraces
+// and the lists rewritten. The StackTrace is not modified.
+// The severities of ExitCode are rewritten by the SeverityPolicy of the ExitPolicy.
+func (p *SeverityPolicy) Apply(st *StackTrace) *StackTrace {
+	if p == nil || st == nil {
+		return st
+	}
+	result := *st
+	if severity, ok := p.rewrite(st); ok {
+		result.SetSeverity(severity)
+	}
+	result.Wrapped = p.Apply(st.Wrapped)
+	if st.List != nil {
+		result.List = make([]*StackTrace, 0, len(st.List))
+		for _, elem := range st.List {
+			result.List = append(result.List, p.Apply(elem))
+		}
+	}
+	return &result
+}
+
+// ApplyTraces returns the copy of the traces with the severities of the stack entries rewritten.
+func (p *SeverityPolicy) ApplyTraces(traces []Trace) []Trace {
+	result := make([]Trace, 0, len(traces))
+	for _, trace := range traces {
+		stack := make([]Stack, len(trace.Stack))
+		copy(stack, trace.Stack)
+		for i := range stack {
+			st := &StackTrace{
+				Severity: stack[i].Severity,
+				Type:     stack[i].Type,
+				Code:     stack[i].Code,
+				Location: stack[i].Location,
+			}
+			if severity, ok := p.rewrite(st); ok {
+				stack[i].Severity = &severity
+			}
+		}
+		trace.Stack = stack
+		result = append(result, trace)
+	}
+	return result
+}
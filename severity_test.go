@@ -0,0 +1,87 @@
+package stacktrace
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSeverityPolicy_Apply(t *testing.T) {
+	newTree := func() *StackTrace {
+		return New("validation failed", WithSeverity(SeverityError)).
+			Append(New("deprecated property", WithSeverity(SeverityWarning), WithType("deprecation"), WithLocation("specs/api.raml"))).
+			Append(New("unknown property", WithSeverity(SeverityWarning), WithType("unknown-property"), WithLocation("specs/legacy/api.raml"))).
+			Append(NewWrapped("in library", New("invalid type", WithSeverity(SeverityError), WithType("validating"), WithLocation("specs/legacy/lib.raml"))))
+	}
+	severities := func(st *StackTrace) []Severity {
+		result := make([]Severity, 0)
+		for _, trace := range st.GetTraces() {
+			stack := trace.Stack[len(trace.Stack)-1]
+			result = append(result, *stack.Severity)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		rules []SeverityRule
+		want  []Severity
+	}{
+		{
+			name:  "Check warnings as errors",
+			rules: []SeverityRule{WarningsAsErrors()},
+			want:  []Severity{SeverityError, SeverityError, SeverityError},
+		},
+		{
+			name:  "Check type demotion",
+			rules: []SeverityRule{{Type: "deprecation", To: SeverityInfo}, WarningsAsErrors()},
+			want:  []Severity{SeverityInfo, SeverityError, SeverityError},
+		},
+		{
+			name:  "Check location demotion",
+			rules: []SeverityRule{{Location: "specs/legacy/**", To: SeverityWarning}, WarningsAsErrors()},
+			want:  []Severity{SeverityError, SeverityWarning, SeverityWarning},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSeverityPolicy(tt.rules...)
+			if err != nil {
+				t.Fatalf("NewSeverityPolicy() error = %v", err)
+			}
+			st := newTree()
+			traces := p.ApplyTraces(st.GetTraces())
+			if got := severities(p.Apply(st)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+			if got, want := severities(st), []Severity{SeverityWarning, SeverityWarning, SeverityError}; !reflect.DeepEqual(got, want) {
+				t.Errorf("Apply() modified the StackTrace: %v, want %v", got, want)
+			}
+			got := make([]Severity, 0, len(traces))
+			for _, trace := range traces {
+				got = append(got, *trace.Stack[len(trace.Stack)-1].Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ApplyTraces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSeverityPolicy(t *testing.T) {
+	fsys := fstest.MapFS{
+		"strict.json":  &fstest.MapFile{Data: []byte(`[{"code": "RAML1023", "to": "info"}, {"severity": "warning", "to": "error"}]`)},
+		"invalid.json": &fstest.MapFile{Data: []byte(`[{"type": "parsing"}]`)},
+	}
+	p, err := LoadSeverityPolicy(fsys, "strict.json")
+	if err != nil {
+		t.Fatalf("LoadSeverityPolicy() error = %v", err)
+	}
+	st := p.Apply(New("message", WithSeverity(SeverityWarning), WithCode("RAML1023")))
+	if *st.Severity != SeverityInfo {
+		t.Errorf("Apply() severity = %v, want %v", *st.Severity, SeverityInfo)
+	}
+	if _, err := LoadSeverityPolicy(fsys, "invalid.json"); err == nil {
+		t.Errorf("LoadSeverityPolicy() expected error for rule without target severity")
+	}
+}
@@ -0,0 +1,88 @@
+package stacktrace
+
+import (
+	"context"
+	"sync"
+)
+
+// Sink receives the diagnostics reported with Report.
+// The implementations must be safe for concurrent use.
+type Sink interface {
+	Report(st *StackTrace)
+}
+
+// Collector is a Sink collecting the reported diagnostics in their order.
+// It is safe for concurrent use.
+type Collector struct {
+	mu   sync.Mutex
+	list []*StackTrace
+}
+
+// NewCollector creates a new empty collector.
+func NewCollector() *Collector {
+	return &Collector{list: make([]*StackTrace, 0)}
+}
+
+// Report adds the StackTrace to the collector.
+func (c *Collector) Report(st *StackTrace) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.list = append(c.list, st)
+}
+
+// Len returns the number of the collected StackTraces.
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.list)
+}
+
+// StackTraces returns a copy of the collected StackTraces.
+func (c *Collector) StackTraces() []*StackTrace {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]*StackTrace, len(c.list))
+	copy(result, c.list)
+	return result
+}
+
+// StackTrace returns a new StackTrace with the given message and options listing the collected StackTraces.
+// It returns nil if nothing was collected.
+func (c *Collector) StackTrace(message string, opts ...Option) *StackTrace {
+	list := c.StackTraces()
+	if len(list) == 0 {
+		return nil
+	}
+	st := New(message, opts...)
+	for _, elem := range list {
+		st.Append(elem)
+	}
+	return st
+}
+
+type sinkKey struct{}
+
+// WithSink returns a copy of the context carrying the sink of the diagnostics reported with Report.
+func WithSink(ctx context.Context, sink Sink) context.Context {
+	return context.WithValue(ctx, sinkKey{}, sink)
+}
+
+// SinkFrom returns the sink carried by the context.
+func SinkFrom(ctx context.Context) (Sink, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	sink, ok := ctx.Value(sinkKey{}).(Sink)
+	return sink, ok && sink != nil
+}
+
+// Report reports the diagnostic to the sink carried by the context, e.g. a non-fatal warning
+// found by a function which does not return it. It is a no-op if the context has no sink or the StackTrace is nil.
+func Report(ctx context.Context, st *StackTrace) {
+	if st == nil {
+		return
+	}
+	if sink, ok := SinkFrom(ctx); ok {
+		sink.Report(st)
+	}
+}
@@ -0,0 +1,56 @@
+package stacktrace
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		sink    bool
+		reports int
+		want    int
+	}{
+		{name: "Test without sink", sink: false, reports: 3, want: 0},
+		{name: "Test with sink", sink: true, reports: 3, want: 3},
+		{name: "Test concurrent reports", sink: true, reports: 100, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCollector()
+			ctx := context.Background()
+			if tt.sink {
+				ctx = WithSink(ctx, c)
+			}
+			var wg sync.WaitGroup
+			for i := 0; i < tt.reports; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					Report(ctx, New("deprecated property", WithSeverity(SeverityWarning)))
+				}()
+			}
+			wg.Wait()
+			Report(ctx, nil)
+			if got := c.Len(); got != tt.want {
+				t.Errorf("Len() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollector_StackTrace(t *testing.T) {
+	c := NewCollector()
+	if st := c.StackTrace("warnings"); st != nil {
+		t.Errorf("StackTrace() = %v, want nil", st)
+	}
+	ctx := WithSink(context.Background(), c)
+	Report(ctx, New("first"))
+	Report(ctx, New("second"))
+	st := c.StackTrace("warnings", WithSeverity(SeverityWarning))
+	if got, want := st.Error(), "warnings; first; second"; got != want {
+		t.Errorf("StackTrace() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+package slogex
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// DefaultDedupWindow is the default time window of the DedupHandler.
+const DefaultDedupWindow = time.Minute
+
+// DedupOptions are the options of the DedupHandler.
+type DedupOptions struct {
+	// Window is the time window in which the identical errors are logged once, defaults to DefaultDedupWindow.
+	Window time.Duration
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+	// FingerprintOpts are the options of the fingerprints identifying the errors.
+	FingerprintOpts []stacktrace.FingerprintOpt
+}
+
+// dedupEntry is the state of an error in the current window.
+type dedupEntry struct {
+	start      time.Time
+	level      slog.Level
+	message    string
+	suppressed int
+	// next is the handler of the first record, the summary is logged with it.
+	next slog.Handler
+}
+
+// dedupState is shared by the handlers derived with WithAttrs and WithGroup.
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	// sweep is the time when the first window of the entries expires, zero if there are no entries.
+	sweep time.Time
+}
+
+// expire removes the entries whose window is expired at the given time and returns the ones with suppressed records.
+func (s *dedupState) expire(now time.Time, window time.Duration) []dedupEntry {
+	if s.sweep.IsZero() || now.Before(s.sweep) {
+		return nil
+	}
+	summaries := make([]dedupEntry, 0)
+	s.sweep = time.Time{}
+	for key, e := range s.entries {
+		end := e.start.Add(window)
+		if now.Before(end) {
+			if s.sweep.IsZero() || end.Before(s.sweep) {
+				s.sweep = end
+			}
+			continue
+		}
+		if e.suppressed > 0 {
+			summaries = append(summaries, *e)
+		}
+		delete(s.entries, key)
+	}
+	return summaries
+}
+
+// DedupHandler is a slog.Handler logging the records of identical errors once per time window.
+// The errors are identified by the message of the record and the fingerprints of the traces of the first error
+// attribute of the record, including the ones inside groups, else of the attributes added with WithAttrs.
+// The expired windows are forgotten on the next record, and a "suppressed N occurrences" summary is logged
+// for the ones with suppressed records. The records without an error are passed as is.
+type DedupHandler struct {
+	next  slog.Handler
+	opts  DedupOptions
+	state *dedupState
+	// err is the first error of the attributes added with WithAttrs.
+	err error
+}
+
+// NewDedupHandler creates a new handler passing the deduplicated records to next.
+// The options can be nil.
+func NewDedupHandler(next slog.Handler, opts *DedupOptions) *DedupHandler {
+	h := &DedupHandler{
+		next:  next,
+		state: &dedupState{entries: make(map[string]*dedupEntry)},
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Window <= 0 {
+		h.opts.Window = DefaultDedupWindow
+	}
+	if h.opts.Now == nil {
+		h.opts.Now = time.Now
+	}
+	return h
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements the slog.Handler interface.
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	r.Attrs(func(a slog.Attr) bool {
+		err = attrError(a)
+		return err == nil
+	})
+	if err == nil {
+		err = h.err
+	}
+	now := h.opts.Now()
+	if err == nil {
+		h.state.mu.Lock()
+		summaries := h.state.expire(now, h.opts.Window)
+		h.state.mu.Unlock()
+		if err := logSummaries(ctx, summaries, now); err != nil {
+			return err
+		}
+		return h.next.Handle(ctx, r)
+	}
+
+	key := r.Message + "\x00" + h.fingerprint(err)
+
+	h.state.mu.Lock()
+	summaries := h.state.expire(now, h.opts.Window)
+	if e, ok := h.state.entries[key]; ok {
+		e.suppressed++
+		h.state.mu.Unlock()
+		return logSummaries(ctx, summaries, now)
+	}
+	h.state.entries[key] = &dedupEntry{start: now, level: r.Level, message: r.Message, next: h.next}
+	if end := now.Add(h.opts.Window); h.state.sweep.IsZero() || end.Before(h.state.sweep) {
+		h.state.sweep = end
+	}
+	h.state.mu.Unlock()
+
+	if err := logSummaries(ctx, summaries, now); err != nil {
+		return err
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// attrError returns the first error of the attribute, including the ones inside groups, nil if there is none.
+func attrError(a slog.Attr) error {
+	if err, ok := a.Value.Any().(error); ok {
+		return err
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return nil
+	}
+	for _, ga := range a.Value.Group() {
+		if err := attrError(ga); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logSummaries logs the summaries of the suppressed occurrences.
+func logSummaries(ctx context.Context, summaries []dedupEntry, now time.Time) error {
+	for i := range summaries {
+		if err := summaries[i].log(ctx, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush logs the summaries of the errors suppressed in the current windows and forgets the expired windows.
+// It can be called periodically, e.g. by a time.Ticker, to log the summaries without waiting for the next record.
+func (h *DedupHandler) Flush(ctx context.Context) error {
+	now := h.opts.Now()
+	summaries := make([]dedupEntry, 0)
+
+	h.state.mu.Lock()
+	for key, e := range h.state.entries {
+		if e.suppressed > 0 {
+			summaries = append(summaries, *e)
+			e.suppressed = 0
+		}
+		if now.Sub(e.start) >= h.opts.Window {
+			delete(h.state.entries, key)
+		}
+	}
+	h.state.mu.Unlock()
+
+	return logSummaries(ctx, summaries, now)
+}
+
+// log logs the summary of the suppressed occurrences.
+func (e *dedupEntry) log(ctx context.Context, now time.Time) error {
+	msg := fmt.Sprintf("suppressed %d occurrences", e.suppressed)
+	if e.suppressed == 1 {
+		msg = "suppressed 1 occurrence"
+	}
+	r := slog.NewRecord(now, e.level, msg, 0)
+	r.AddAttrs(
+		slog.String("message", e.message),
+		slog.Int("suppressed", e.suppressed),
+		slog.Time("since", e.start),
+	)
+	return e.next.Handle(ctx, r)
+}
+
+// fingerprint returns the fingerprint of the error.
+func (h *DedupHandler) fingerprint(err error) string {
+	traces := fromError(err).GetTraces(stacktrace.WithFingerprints(h.opts.FingerprintOpts...))
+	fingerprints := make([]string, 0, len(traces))
+	for i := range traces {
+		fingerprints = append(fingerprints, traces[i].Fingerprint)
+	}
+	if len(fingerprints) == 0 {
+		return err.Error()
+	}
+	return strings.Join(fingerprints, ",")
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	err := h.err
+	for i := 0; err == nil && i < len(attrs); i++ {
+		err = attrError(attrs[i])
+	}
+	return &DedupHandler{next: h.next.WithAttrs(attrs), opts: h.opts, state: h.state, err: err}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{next: h.next.WithGroup(name), opts: h.opts, state: h.state, err: h.err}
+}
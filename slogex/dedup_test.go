@@ -0,0 +1,112 @@
+package slogex
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestDedupHandler(t *testing.T) {
+	var buf bytes.Buffer
+	next := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			if a.Key == "since" {
+				return slog.String(a.Key, a.Value.Time().Format("15:04:05"))
+			}
+			return a
+		},
+	})
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	h := NewDedupHandler(NewHandler(next, &HandlerOptions{Layout: &Layout{Mode: LayoutJSON, LeafOnly: true}}), &DedupOptions{
+		Window: time.Minute,
+		Now:    func() time.Time { return now },
+	})
+	logger := slog.New(h)
+	diagnostic := func(line int) error {
+		return stacktrace.New("unknown property",
+			stacktrace.WithLocation("api.raml"),
+			stacktrace.WithPosition(stacktrace.NewPosition(line, 1)),
+		)
+	}
+
+	logger.Error("validation failed", "err", diagnostic(3))
+	now = now.Add(10 * time.Second)
+	logger.Error("validation failed", "err", diagnostic(3))
+	logger.Error("validation failed", "err", diagnostic(3))
+	logger.Error("validation failed", "err", diagnostic(4))
+	logger.Info("no error")
+	now = now.Add(time.Minute)
+	logger.Error("validation failed", "err", diagnostic(3))
+	now = now.Add(10 * time.Second)
+	logger.Error("validation failed", "err", diagnostic(3))
+	if err := h.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	const trace3 = `"{\"traces\":[{\"message\":\"unknown property\",\"position\":\"api.raml:3:1\"}]}"`
+	const trace4 = `"{\"traces\":[{\"message\":\"unknown property\",\"position\":\"api.raml:4:1\"}]}"`
+	want := strings.Join([]string{
+		`level=ERROR msg="validation failed" err=` + trace3,
+		`level=ERROR msg="validation failed" err=` + trace4,
+		`level=INFO msg="no error"`,
+		`level=ERROR msg="suppressed 2 occurrences" message="validation failed" suppressed=2 since=10:00:00`,
+		`level=ERROR msg="validation failed" err=` + trace3,
+		`level=ERROR msg="suppressed 1 occurrence" message="validation failed" suppressed=1 since=10:01:10`,
+	}, "\n")
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("log =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDedupHandler_Expire(t *testing.T) {
+	var buf bytes.Buffer
+	next := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == "since") {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	now := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	h := NewDedupHandler(next, &DedupOptions{Window: time.Minute, Now: func() time.Time { return now }})
+	logger := slog.New(h)
+	diagnostic := func(name string) error {
+		return stacktrace.New(name, stacktrace.WithLocation("api.raml"))
+	}
+
+	for i := 0; i < 3; i++ {
+		logger.With("err", diagnostic("bound")).Error("bound failed")
+		logger.Error("grouped failed", slog.Group("request", slog.Any("err", diagnostic("grouped"))))
+	}
+	for i := 0; i < 100; i++ {
+		logger.Error("once", "err", diagnostic(fmt.Sprintf("error %d", i)))
+	}
+	now = now.Add(time.Minute)
+	buf.Reset()
+	logger.Info("later")
+
+	want := strings.Join([]string{
+		`level=ERROR msg="suppressed 2 occurrences" err.position=api.raml:1 err.message=bound message="bound failed" suppressed=2`,
+		`level=ERROR msg="suppressed 2 occurrences" message="grouped failed" suppressed=2`,
+		`level=INFO msg=later`,
+	}, "\n")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	sort.Strings(lines[:len(lines)-1])
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Errorf("log =\n%s\nwant\n%s", got, want)
+	}
+	if n := len(h.state.entries); n != 0 {
+		t.Errorf("entries = %d, want the expired entries removed", n)
+	}
+}
@@ -0,0 +1,41 @@
+package slogex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// fromError converts the Go error tree to a StackTrace tree.
+// The errors which are not StackTraces get their Go type name as the type. An error wrapping another one
+// wraps its StackTrace, with the message of the wrapped error removed from its own, and an error joining
+// several ones gets their StackTraces in its list.
+func fromError(err error) *stacktrace.StackTrace {
+	if st, ok := stacktrace.Unwrap(err); ok {
+		return st
+	}
+	errType := stacktrace.WithType(stacktrace.Type(fmt.Sprintf("%T", err)))
+	switch u := err.(type) {
+	case interface{ Unwrap() []error }:
+		// the message of the joined errors is the one of its branches
+		st := stacktrace.New("", errType).SetErr(err)
+		for _, e := range u.Unwrap() {
+			if e != nil {
+				st.Append(fromError(e))
+			}
+		}
+		return st
+	case interface{ Unwrap() error }:
+		inner := u.Unwrap()
+		if inner == nil {
+			break
+		}
+		msg := err.Error()
+		if innerMsg := inner.Error(); strings.HasSuffix(msg, innerMsg) {
+			msg = strings.TrimSuffix(strings.TrimSuffix(msg, innerMsg), ": ")
+		}
+		return stacktrace.New(msg, errType).Wrap(fromError(inner)).SetErr(err)
+	}
+	return stacktrace.New(err.Error(), errType).SetErr(err)
+}
@@ -0,0 +1,65 @@
+package slogex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestErrToSlogAttr_ErrorTrees(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Test wrapped chain",
+			err:  fmt.Errorf("load spec: %w", &fs.PathError{Op: "open", Path: "api.raml", Err: fs.ErrNotExist}),
+			want: `{"tracebacks":{"traces":{"0":{"stack":{` +
+				`"0":{"type":"*fmt.wrapError","message":"load spec"},` +
+				`"1":{"type":"*fs.PathError","message":"open api.raml"},` +
+				`"2":{"type":"*errors.errorString","message":"file does not exist"}}}}}}`,
+		},
+		{
+			name: "Test joined errors",
+			err: errors.Join(
+				errors.New("first"),
+				stacktrace.New("second", stacktrace.WithType("parsing"), stacktrace.WithLocation("api.raml")),
+			),
+			want: `{"tracebacks":{"traces":{` +
+				`"0":{"stack":{"0":{"type":"*errors.errorString","message":"first"}}},` +
+				`"1":{"stack":{"0":{"type":"parsing","position":"api.raml:1","message":"second"}}}}}}`,
+		},
+		{
+			name: "Test joined errors below a wrapper",
+			err:  fmt.Errorf("validate: %w", errors.Join(errors.New("first"), errors.New("second"))),
+			want: `{"tracebacks":{"traces":{` +
+				`"0":{"stack":{"0":{"type":"*fmt.wrapError","message":"validate"},"1":{"type":"*errors.errorString","message":"first"}}},` +
+				`"1":{"stack":{"0":{"type":"*fmt.wrapError","message":"validate"},"1":{"type":"*errors.errorString","message":"second"}}}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			logger.LogAttrs(context.Background(), slog.LevelError, "", ErrToSlogAttr(tt.err))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("ErrToSlogAttr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
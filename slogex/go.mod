@@ -2,4 +2,7 @@ module github.com/acronis/go-stacktrace/slogex
 
 go 1.22.6
 
-require github.com/acronis/go-stacktrace v0.5.0
+require github.com/acronis/go-stacktrace v0.6.0
+
+// the catalog and the error codes are released in v0.6.0, the local module is used until it is tagged
+replace github.com/acronis/go-stacktrace => ../
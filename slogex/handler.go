@@ -0,0 +1,93 @@
+package slogex
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// HandlerOptions are the options of the Handler.
+type HandlerOptions struct {
+	// TracesOpts are the options of the traces of the expanded StackTraces.
+	TracesOpts []stacktrace.TracesOpt
+	// Layout is the layout of the expanded StackTraces, defaults to DefaultLayout.
+	Layout *Layout
+}
+
+// Handler is a slog.Handler expanding the errors into the structured traces built by the layout.
+// The attributes of the records and the ones added with WithAttrs are rewritten, including the ones inside groups.
+// The rewritten attribute keeps its key, except in the LayoutOTel mode whose attributes are inlined.
+type Handler struct {
+	next slog.Handler
+	opts HandlerOptions
+}
+
+// NewHandler creates a new handler passing the rewritten records to next.
+// The options can be nil.
+func NewHandler(next slog.Handler, opts *HandlerOptions) *Handler {
+	h := &Handler{next: next}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements the slog.Handler interface.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	rewritten := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		rewritten.AddAttrs(h.rewrite(a))
+		return true
+	})
+	return h.next.Handle(ctx, rewritten)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	rewritten := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		rewritten = append(rewritten, h.rewrite(a))
+	}
+	return &Handler{next: h.next.WithAttrs(rewritten), opts: h.opts}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// rewrite returns the attribute with the StackTraces expanded.
+func (h *Handler) rewrite(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		// the StackTrace is a slog.LogValuer, so the errors are checked before resolving
+		if err, ok := a.Value.Any().(error); ok {
+			layout := h.opts.Layout
+			if layout == nil {
+				layout = &DefaultLayout
+			}
+			if layout.Mode == LayoutOTel {
+				// the semantic convention attributes are inlined
+				return layout.Attr(err, h.opts.TracesOpts...)
+			}
+			return slog.Attr{Key: a.Key, Value: layout.Attr(err, h.opts.TracesOpts...).Value}
+		}
+		if a.Value.Kind() == slog.KindLogValuer {
+			return h.rewrite(slog.Attr{Key: a.Key, Value: a.Value.Resolve()})
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, ga := range group {
+			attrs = append(attrs, h.rewrite(ga))
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	}
+	return a
+}
@@ -0,0 +1,105 @@
+package slogex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestHandler(t *testing.T) {
+	st := func() error {
+		return stacktrace.New("error message",
+			stacktrace.WithLocation("location.raml"),
+			stacktrace.WithType("parsing"),
+		)
+	}
+	const traces = `{"traces":{"0":{"stack":{"0":{"type":"parsing","position":"location.raml:1","message":"error message"}}}}}`
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want string
+	}{
+		{
+			name: "Test top-level error",
+			log: func(logger *slog.Logger) {
+				logger.Error("failed", "err", st())
+			},
+			want: `{"msg":"failed","err":` + traces + `}`,
+		},
+		{
+			name: "Test wrapped error",
+			log: func(logger *slog.Logger) {
+				logger.Error("failed", "err", fmt.Errorf("load: %w", st()))
+			},
+			want: `{"msg":"failed","err":{"traces":{"0":{"stack":{"0":{"message":"load: parsing: location.raml:1"},` +
+				`"1":{"type":"parsing","position":"location.raml:1","message":"error message"}}}}}}`,
+		},
+		{
+			name: "Test error in group",
+			log: func(logger *slog.Logger) {
+				logger.Error("failed", slog.Group("request", slog.String("id", "1"), slog.Any("err", st())))
+			},
+			want: `{"msg":"failed","request":{"id":"1","err":` + traces + `}}`,
+		},
+		{
+			name: "Test WithAttrs and WithGroup",
+			log: func(logger *slog.Logger) {
+				logger.With("err", st()).WithGroup("g").Error("failed", "cause", st())
+			},
+			want: `{"msg":"failed","err":` + traces + `,"g":{"cause":` + traces + `}}`,
+		},
+		{
+			name: "Test plain error",
+			log: func(logger *slog.Logger) {
+				logger.Error("failed", "err", errors.New("boom"))
+			},
+			want: `{"msg":"failed","err":{"traces":{"0":{"stack":{"0":{"type":"*errors.errorString","message":"boom"}}}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.log(slog.New(NewHandler(next, nil)))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("Handle() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_OTel(t *testing.T) {
+	var buf bytes.Buffer
+	next := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	logger := slog.New(NewHandler(next, &HandlerOptions{Layout: &Layout{Mode: LayoutOTel}}))
+	logger.Error("failed", "err", stacktrace.New("unknown property",
+		stacktrace.WithType("parsing"),
+		stacktrace.WithSeverity(stacktrace.SeverityError),
+		stacktrace.WithLocation("api.raml"),
+		stacktrace.WithPosition(stacktrace.NewPosition(7, 3)),
+	))
+	want := `{"msg":"failed","exception.type":"parsing","exception.message":"unknown property",` +
+		`"exception.stacktrace":"error: parsing: api.raml:7:3: unknown property","code.filepath":"api.raml","code.lineno":7}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("Handle() = %s, want %s", got, want)
+	}
+}
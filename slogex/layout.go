@@ -0,0 +1,312 @@
+package slogex
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// LayoutMode is the shape of the traces attribute.
+type LayoutMode int
+
+const (
+	// LayoutNested emits the traces as nested groups.
+	LayoutNested LayoutMode = iota
+	// LayoutFlat emits the traces as top-level attributes with dotted keys, e.g. "tracebacks.traces.0.stack.0.message".
+	LayoutFlat
+	// LayoutJSON emits the traces as a single JSON string with arrays of traces and stack entries.
+	LayoutJSON
+	// LayoutOTel emits the top-level exception and code attributes of the OpenTelemetry semantic conventions,
+	// the traces are rendered one per line in "exception.stacktrace". The key names of the layout are not used.
+	LayoutOTel
+)
+
+// Attribute keys of the OpenTelemetry semantic conventions.
+const (
+	OTelExceptionType       = "exception.type"
+	OTelExceptionMessage    = "exception.message"
+	OTelExceptionStacktrace = "exception.stacktrace"
+	OTelCodeFilepath        = "code.filepath"
+	OTelCodeLineno          = "code.lineno"
+)
+
+// Layout describes the attributes built from the errors.
+// The empty keys are replaced by the default ones, e.g. "tracebacks" for RootKey.
+type Layout struct {
+	// RootKey is the key of the traces attribute.
+	RootKey string
+	// ErrorKey is the key of the attribute of the errors which cannot be laid out.
+	ErrorKey string
+	// TracesKey is the key of the traces.
+	TracesKey string
+	// StackKey is the key of the stack entries of a trace.
+	StackKey string
+
+	// Keys of the fields of the stack entries.
+	TypeKey     string
+	CodeKey     string
+	HelpKey     string
+	SeverityKey string
+	PositionKey string
+	MessageKey  string
+	InfoKey     string
+	TemplateKey string
+	ArgsKey     string
+
+	// Mode is the shape of the traces attribute.
+	Mode LayoutMode
+	// LeafOnly emits only the innermost stack entry of every trace, directly under the trace index.
+	LeafOnly bool
+	// PadIndices zero-pads the indices of the traces and the stack entries to the same width, e.g. "01", "02", ..., "10".
+	PadIndices bool
+	// Arrays emits the traces and the stack entries as arrays instead of groups keyed by their indices
+	// in the nested mode.
+	Arrays bool
+}
+
+// DefaultLayout is the layout used by ErrToSlogAttr.
+var DefaultLayout = Layout{PadIndices: true}
+
+// key returns the key, or the default one if it is empty.
+func key(k, def string) string {
+	if k == "" {
+		return def
+	}
+	return k
+}
+
+// index returns the key of the i-th of n elements.
+func (l *Layout) index(i, n int) string {
+	if !l.PadIndices {
+		return strconv.Itoa(i)
+	}
+	return fmt.Sprintf("%0*d", len(strconv.Itoa(n)), i)
+}
+
+// Attr returns the attribute of the error laid out by the layout.
+// The Go error trees are walked through their Unwrap methods, including the joined errors, and the errors
+// which are not StackTraces get their Go type name, e.g. "*fs.PathError", as the type.
+// It returns an empty attribute if the error is nil.
+func (l *Layout) Attr(err error, opts ...stacktrace.TracesOpt) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	st := fromError(err)
+	if st == nil {
+		return slog.String(key(l.ErrorKey, "error"), "nil stacktrace")
+	}
+
+	return l.tracesAttr(err, st, st.GetTraces(opts...))
+}
+
+// tracesAttr returns the attribute of the given traces of the error.
+func (l *Layout) tracesAttr(err error, st *stacktrace.StackTrace, traces []stacktrace.Trace) slog.Attr {
+	if l.Mode == LayoutOTel {
+		return slog.Attr{Key: "", Value: slog.GroupValue(otelAttrs(err, st, traces)...)}
+	}
+	entries := make([][][]slog.Attr, 0, len(traces))
+	for i := range traces {
+		stack := traces[i].Stack
+		if l.LeafOnly && len(stack) > 0 {
+			stack = stack[len(stack)-1:]
+		}
+		fields := make([][]slog.Attr, 0, len(stack))
+		for j := range stack {
+			fields = append(fields, l.stackAttrs(&stack[j]))
+		}
+		entries = append(entries, fields)
+	}
+
+	switch l.Mode {
+	case LayoutFlat:
+		return slog.Attr{Key: "", Value: slog.GroupValue(l.flat(entries)...)}
+	case LayoutJSON:
+		data, err := json.Marshal(map[string]any{key(l.TracesKey, "traces"): l.values(entries)})
+		if err != nil {
+			return slog.String(key(l.ErrorKey, "error"), err.Error())
+		}
+		return slog.String(key(l.RootKey, "tracebacks"), string(data))
+	default:
+		if l.Arrays {
+			return slog.Group(key(l.RootKey, "tracebacks"), slog.Any(key(l.TracesKey, "traces"), l.values(entries)))
+		}
+		return slog.Group(key(l.RootKey, "tracebacks"), l.nested(entries))
+	}
+}
+
+// stackAttrs returns the fields of the stack entry.
+func (l *Layout) stackAttrs(stack *stacktrace.Stack) []slog.Attr {
+	attrs := make([]slog.Attr, 0)
+	if stack.Type != nil {
+		attrs = append(attrs, slog.String(key(l.TypeKey, "type"), stack.Type.String()))
+	}
+	if stack.Code != nil {
+		attrs = append(attrs, slog.String(key(l.CodeKey, "code"), stack.Code.String()))
+		if entry, ok := stacktrace.Lookup(*stack.Code); ok && entry.HelpURL != "" {
+			attrs = append(attrs, slog.String(key(l.HelpKey, "help"), entry.HelpURL))
+		}
+	}
+	if stack.Severity != nil {
+		attrs = append(attrs, slog.String(key(l.SeverityKey, "severity"), stack.Severity.String()))
+	}
+	if stack.LinePos != nil {
+		attrs = append(attrs, slog.String(key(l.PositionKey, "position"), *stack.LinePos))
+	}
+	// the Info is written as a group, so the message is written without it
+	if len(stack.Info) > 0 {
+		attrs = append(attrs,
+			slog.String(key(l.MessageKey, "message"), stack.Text),
+			infoAttr(key(l.InfoKey, "info"), stack.Info),
+		)
+	} else {
+		attrs = append(attrs, slog.String(key(l.MessageKey, "message"), stack.Message))
+	}
+	if stack.Template != "" {
+		attrs = append(attrs,
+			slog.String(key(l.TemplateKey, "template"), stack.Template),
+			argsAttr(key(l.ArgsKey, "args"), stack.Args),
+		)
+	}
+	return attrs
+}
+
+// nested returns the traces as nested groups keyed by their indices.
+func (l *Layout) nested(entries [][][]slog.Attr) slog.Attr {
+	traceAttrs := make([]slog.Attr, 0, len(entries))
+	for i, stack := range entries {
+		var traceValue slog.Value
+		if l.LeafOnly {
+			var fields []slog.Attr
+			if len(stack) > 0 {
+				fields = stack[0]
+			}
+			traceValue = slog.GroupValue(fields...)
+		} else {
+			stackAttrs := make([]slog.Attr, 0, len(stack))
+			for j, fields := range stack {
+				stackAttrs = append(stackAttrs, slog.Attr{Key: l.index(j, len(stack)), Value: slog.GroupValue(fields...)})
+			}
+			traceValue = slog.GroupValue(slog.Attr{Key: key(l.StackKey, "stack"), Value: slog.GroupValue(stackAttrs...)})
+		}
+		traceAttrs = append(traceAttrs, slog.Attr{Key: l.index(i, len(entries)), Value: traceValue})
+	}
+	return slog.Attr{Key: key(l.TracesKey, "traces"), Value: slog.GroupValue(traceAttrs...)}
+}
+
+// flat returns the traces as attributes with dotted keys.
+func (l *Layout) flat(entries [][][]slog.Attr) []slog.Attr {
+	result := make([]slog.Attr, 0)
+	prefix := key(l.RootKey, "tracebacks") + "." + key(l.TracesKey, "traces")
+	for i, stack := range entries {
+		tracePrefix := prefix + "." + l.index(i, len(entries))
+		for j, fields := range stack {
+			stackPrefix := tracePrefix
+			if !l.LeafOnly {
+				stackPrefix += "." + key(l.StackKey, "stack") + "." + l.index(j, len(stack))
+			}
+			result = appendFlat(result, stackPrefix, fields)
+		}
+	}
+	return result
+}
+
+// appendFlat appends the attributes with the prefixed keys, flattening the groups.
+func appendFlat(result []slog.Attr, prefix string, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		k := prefix + "." + a.Key
+		if a.Value.Kind() == slog.KindGroup {
+			result = appendFlat(result, k, a.Value.Group())
+			continue
+		}
+		result = append(result, slog.Attr{Key: k, Value: a.Value})
+	}
+	return result
+}
+
+// values returns the traces as a value encoded as arrays, e.g. by json.Marshal.
+func (l *Layout) values(entries [][][]slog.Attr) []any {
+	traces := make([]any, 0, len(entries))
+	for _, stack := range entries {
+		if l.LeafOnly {
+			var fields map[string]any
+			if len(stack) > 0 {
+				fields = attrsMap(stack[0])
+			}
+			traces = append(traces, fields)
+			continue
+		}
+		stackValues := make([]any, 0, len(stack))
+		for _, fields := range stack {
+			stackValues = append(stackValues, attrsMap(fields))
+		}
+		traces = append(traces, map[string]any{key(l.StackKey, "stack"): stackValues})
+	}
+	return traces
+}
+
+// attrsMap returns the attributes as a map, the groups are converted to nested maps.
+func attrsMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup {
+			m[a.Key] = attrsMap(a.Value.Group())
+			continue
+		}
+		m[a.Key] = a.Value.Any()
+	}
+	return m
+}
+
+// otelAttrs returns the exception and code attributes of the error.
+// The exception message and stacktrace are built from the traces, so they are localized and redacted alike.
+// The code attributes are the location of the first diagnostic with a location.
+func otelAttrs(err error, st *stacktrace.StackTrace, traces []stacktrace.Trace) []slog.Attr {
+	errType := st.Type.String()
+	if errType == "" {
+		errType = fmt.Sprintf("%T", err)
+	}
+	lines := make([]string, 0, len(traces))
+	messages := make([]string, 0, len(traces))
+	for i := range traces {
+		lines = append(lines, traces[i].String())
+		messages = append(messages, traceMessage(&traces[i]))
+	}
+	attrs := []slog.Attr{
+		slog.String(OTelExceptionType, errType),
+		slog.String(OTelExceptionMessage, strings.Join(messages, "; ")),
+		slog.String(OTelExceptionStacktrace, strings.Join(lines, "\n")),
+	}
+	for i := range traces {
+		stack := traces[i].Stack
+		for j := len(stack) - 1; j >= 0; j-- {
+			if stack[j].Location == nil {
+				continue
+			}
+			line := 1
+			if stack[j].Position != nil && stack[j].Position.Line > 0 {
+				line = stack[j].Position.Line
+			}
+			return append(attrs,
+				slog.String(OTelCodeFilepath, stack[j].Location.String()),
+				slog.Int(OTelCodeLineno, line),
+			)
+		}
+	}
+	return attrs
+}
+
+// traceMessage returns the messages of the stack entries of the trace joined as in the errors.
+func traceMessage(trace *stacktrace.Trace) string {
+	segs := make([]string, 0, len(trace.Stack))
+	for i := range trace.Stack {
+		if msg := trace.Stack[i].Message; msg != "" {
+			segs = append(segs, msg)
+		}
+	}
+	return strings.Join(segs, ": ")
+}
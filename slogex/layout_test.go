@@ -0,0 +1,125 @@
+package slogex
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestLayout_Attr(t *testing.T) {
+	err := stacktrace.NewWrapped("invalid document",
+		stacktrace.New("unknown property",
+			stacktrace.WithLocation("api.raml"),
+			stacktrace.WithType("parsing"),
+		),
+		stacktrace.WithLocation("main.raml"),
+	)
+	tests := []struct {
+		name   string
+		layout Layout
+		want   string
+	}{
+		{
+			name:   "Test default",
+			layout: DefaultLayout,
+			want: `{"tracebacks":{"traces":{"0":{"stack":{"0":{"position":"main.raml:1","message":"invalid document"},` +
+				`"1":{"type":"parsing","position":"api.raml:1","message":"unknown property"}}}}}}`,
+		},
+		{
+			name:   "Test keys",
+			layout: Layout{RootKey: "error", TracesKey: "t", StackKey: "s", MessageKey: "msg", TypeKey: "kind", PositionKey: "at"},
+			want: `{"error":{"t":{"0":{"s":{"0":{"at":"main.raml:1","msg":"invalid document"},` +
+				`"1":{"kind":"parsing","at":"api.raml:1","msg":"unknown property"}}}}}}`,
+		},
+		{
+			name:   "Test leaf only",
+			layout: Layout{LeafOnly: true},
+			want:   `{"tracebacks":{"traces":{"0":{"type":"parsing","position":"api.raml:1","message":"unknown property"}}}}`,
+		},
+		{
+			name:   "Test flat",
+			layout: Layout{Mode: LayoutFlat, LeafOnly: true},
+			want: `{"tracebacks.traces.0.type":"parsing","tracebacks.traces.0.position":"api.raml:1",` +
+				`"tracebacks.traces.0.message":"unknown property"}`,
+		},
+		{
+			name:   "Test arrays",
+			layout: Layout{Arrays: true},
+			want: `{"tracebacks":{"traces":[{"stack":[{"message":"invalid document","position":"main.raml:1"},` +
+				`{"message":"unknown property","position":"api.raml:1","type":"parsing"}]}]}}`,
+		},
+		{
+			name:   "Test OTel",
+			layout: Layout{Mode: LayoutOTel},
+			want: `{"exception.type":"*stacktrace.StackTrace","exception.message":"invalid document: unknown property",` +
+				`"exception.stacktrace":"main.raml:1: invalid document: parsing: api.raml:1: unknown property",` +
+				`"code.filepath":"api.raml","code.lineno":1}`,
+		},
+		{
+			name:   "Test JSON",
+			layout: Layout{Mode: LayoutJSON, LeafOnly: true},
+			want:   `{"tracebacks":"{\"traces\":[{\"message\":\"unknown property\",\"position\":\"api.raml:1\",\"type\":\"parsing\"}]}"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey || a.Key == slog.MessageKey) {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			logger.LogAttrs(context.Background(), slog.LevelError, "", tt.layout.Attr(err))
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("Attr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout_AttrOTelRedacted(t *testing.T) {
+	err := stacktrace.New("validation failed").
+		Append(stacktrace.New("login failed", stacktrace.WithLocation("api.raml"), stacktrace.WithInfo("password", "hunter2"))).
+		Append(stacktrace.New("token Bearer abc.def rejected", stacktrace.WithLocation("api.raml")))
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), &HandlerOptions{
+		Layout:     &Layout{Mode: LayoutOTel},
+		TracesOpts: []stacktrace.TracesOpt{stacktrace.WithRedactionPolicy(stacktrace.DefaultRedactionPolicy())},
+	}))
+	logger.Error("failed", slog.Any("error", err))
+	got := buf.String()
+	for _, secret := range []string{"hunter2", "abc.def"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("log = %s, must not contain %s", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"exception.message":"login failed: password: [REDACTED]; token [REDACTED] rejected"`) {
+		t.Errorf("log = %s, want the redacted exception message", got)
+	}
+}
+
+func TestLayout_index(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+		i, n   int
+		want   string
+	}{
+		{name: "Test padded", layout: Layout{PadIndices: true}, i: 3, n: 12, want: "03"},
+		{name: "Test plain", layout: Layout{}, i: 3, n: 12, want: "3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.layout.index(tt.i, tt.n); got != tt.want {
+				t.Errorf("index() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
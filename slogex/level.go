@@ -0,0 +1,151 @@
+package slogex
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+// LevelMapping maps the severities to the slog levels.
+type LevelMapping struct {
+	// Levels maps the severities.
+	Levels map[stacktrace.Severity]slog.Level
+	// Default is the level of the traces without a mapped severity.
+	Default slog.Level
+}
+
+// DefaultLevelMapping returns the mapping of the known severities: critical to Error+4, error to Error,
+// warning to Warn and info to Info. The traces without a severity are logged as errors.
+func DefaultLevelMapping() *LevelMapping {
+	return &LevelMapping{
+		Levels: map[stacktrace.Severity]slog.Level{
+			stacktrace.SeverityCritical: slog.LevelError + 4,
+			stacktrace.SeverityError:    slog.LevelError,
+			stacktrace.SeverityWarning:  slog.LevelWarn,
+			stacktrace.SeverityInfo:     slog.LevelInfo,
+		},
+		Default: slog.LevelError,
+	}
+}
+
+// Level returns the level of the severity, the default level if the severity is nil or not mapped.
+func (m *LevelMapping) Level(severity *stacktrace.Severity) slog.Level {
+	if severity == nil {
+		return m.Default
+	}
+	if level, ok := m.Levels[*severity]; ok {
+		return level
+	}
+	return m.Default
+}
+
+// higherSeverity returns the more severe of the severities, the nil severities are ignored.
+func higherSeverity(a, b *stacktrace.Severity) *stacktrace.Severity {
+	if a == nil || b != nil && stacktrace.SeverityRank(*b) > stacktrace.SeverityRank(*a) {
+		return b
+	}
+	return a
+}
+
+// traceSeverity returns the highest severity of the trace, nil if it has none.
+func traceSeverity(trace *stacktrace.Trace) *stacktrace.Severity {
+	var severity *stacktrace.Severity
+	for i := range trace.Stack {
+		severity = higherSeverity(severity, trace.Stack[i].Severity)
+	}
+	return severity
+}
+
+// Reporter logs the errors at the levels of their severities.
+type Reporter struct {
+	// Levels maps the severities to the levels, defaults to DefaultLevelMapping.
+	Levels *LevelMapping
+	// Layout is the layout of the logged traces, defaults to DefaultLayout.
+	Layout *Layout
+	// Message is the message of the records, defaults to the message of the innermost stack entry of the trace,
+	// or to the messages of the traces in the Tree mode, localized and redacted with the traces options.
+	Message string
+	// Tree logs the whole tree in one record at the level of its highest severity instead of a record per trace.
+	Tree bool
+}
+
+// Log logs the error with the logger, a record per trace at the level of the trace's highest severity.
+// The source of the records is the caller of Log.
+func (r *Reporter) Log(ctx context.Context, logger *slog.Logger, err error, opts ...stacktrace.TracesOpt) {
+	r.log(ctx, logger, err, callerPC(), opts...)
+}
+
+// callerPC returns the program counter of the caller of the function calling it.
+func callerPC() uintptr {
+	var pcs [1]uintptr
+	// skip runtime.Callers, callerPC and its caller
+	runtime.Callers(3, pcs[:])
+	return pcs[0]
+}
+
+// logAttrs logs a record with the given source program counter, as slog.Logger.LogAttrs does for its caller.
+func logAttrs(ctx context.Context, logger *slog.Logger, level slog.Level, msg string, pc uintptr, attrs ...slog.Attr) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, msg, pc)
+	record.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+// log logs the error with the source at the given program counter.
+func (r *Reporter) log(ctx context.Context, logger *slog.Logger, err error, pc uintptr, opts ...stacktrace.TracesOpt) {
+	if err == nil {
+		return
+	}
+	levels := r.Levels
+	if levels == nil {
+		levels = DefaultLevelMapping()
+	}
+	layout := r.Layout
+	if layout == nil {
+		layout = &DefaultLayout
+	}
+	st := fromError(err)
+	traces := st.GetTraces(opts...)
+
+	if r.Tree {
+		var severity *stacktrace.Severity
+		for i := range traces {
+			severity = higherSeverity(severity, traceSeverity(&traces[i]))
+		}
+		msg := r.Message
+		if msg == "" {
+			messages := make([]string, 0, len(traces))
+			for i := range traces {
+				messages = append(messages, traceMessage(&traces[i]))
+			}
+			msg = strings.Join(messages, "; ")
+		}
+		logAttrs(ctx, logger, levels.Level(severity), msg, pc, layout.tracesAttr(err, st, traces))
+		return
+	}
+
+	for i := range traces {
+		trace := traces[i]
+		level := levels.Level(traceSeverity(&trace))
+		msg := r.Message
+		if msg == "" && len(trace.Stack) > 0 {
+			msg = trace.Stack[len(trace.Stack)-1].Message
+		}
+		logAttrs(ctx, logger, level, msg, pc, layout.tracesAttr(err, st, []stacktrace.Trace{trace}))
+	}
+}
+
+// Log logs the error with the logger, a record per trace at the level of the trace's highest severity
+// mapped by DefaultLevelMapping.
+func Log(ctx context.Context, logger *slog.Logger, err error, opts ...stacktrace.TracesOpt) {
+	(&Reporter{}).log(ctx, logger, err, callerPC(), opts...)
+}
@@ -0,0 +1,119 @@
+package slogex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/acronis/go-stacktrace"
+)
+
+func TestLevelMapping_Level(t *testing.T) {
+	severity := func(s stacktrace.Severity) *stacktrace.Severity { return &s }
+	tests := []struct {
+		name     string
+		severity *stacktrace.Severity
+		want     slog.Level
+	}{
+		{name: "Test critical", severity: severity(stacktrace.SeverityCritical), want: slog.LevelError + 4},
+		{name: "Test warning", severity: severity(stacktrace.SeverityWarning), want: slog.LevelWarn},
+		{name: "Test unknown", severity: severity("fatal"), want: slog.LevelError},
+		{name: "Test nil", severity: nil, want: slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultLevelMapping().Level(tt.severity); got != tt.want {
+				t.Errorf("Level() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReporter_Log(t *testing.T) {
+	report := func() error {
+		return stacktrace.New("invalid document").
+			Append(stacktrace.New("deprecated property", stacktrace.WithSeverity(stacktrace.SeverityWarning), stacktrace.WithLocation("a.raml"))).
+			Append(stacktrace.New("unknown property", stacktrace.WithSeverity(stacktrace.SeverityError), stacktrace.WithLocation("b.raml")))
+	}
+	tests := []struct {
+		name     string
+		reporter *Reporter
+		err      error
+		want     []string
+	}{
+		{
+			name:     "Test per trace",
+			reporter: &Reporter{Layout: &Layout{Mode: LayoutFlat, LeafOnly: true}},
+			err:      report(),
+			want: []string{
+				`level=WARN msg="deprecated property" tracebacks.traces.0.severity=warning tracebacks.traces.0.position=a.raml:1 tracebacks.traces.0.message="deprecated property"`,
+				`level=ERROR msg="unknown property" tracebacks.traces.0.severity=error tracebacks.traces.0.position=b.raml:1 tracebacks.traces.0.message="unknown property"`,
+			},
+		},
+		{
+			name:     "Test whole tree",
+			reporter: &Reporter{Layout: &Layout{Mode: LayoutJSON, LeafOnly: true}, Message: "validation failed", Tree: true},
+			err:      report(),
+			want: []string{
+				`level=ERROR msg="validation failed" tracebacks="{\"traces\":[` +
+					`{\"message\":\"deprecated property\",\"position\":\"a.raml:1\",\"severity\":\"warning\"},` +
+					`{\"message\":\"unknown property\",\"position\":\"b.raml:1\",\"severity\":\"error\"}]}"`,
+			},
+		},
+		{
+			name: "Test custom levels",
+			reporter: &Reporter{
+				Levels: &LevelMapping{Levels: map[stacktrace.Severity]slog.Level{stacktrace.SeverityError: slog.LevelWarn}, Default: slog.LevelInfo},
+				Layout: &Layout{Mode: LayoutFlat, LeafOnly: true},
+			},
+			err: errors.New("boom"),
+			want: []string{
+				`level=INFO msg=boom tracebacks.traces.0.type=*errors.errorString tracebacks.traces.0.message=boom`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}))
+			tt.reporter.Log(context.Background(), logger, tt.err)
+			if got, want := strings.TrimSpace(buf.String()), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("Log() = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestReporter_LogTreeRedactedWithSource(t *testing.T) {
+	err := stacktrace.New("validation failed").
+		Append(stacktrace.New("login failed", stacktrace.WithLocation("a.raml"), stacktrace.WithInfo("password", "hunter2")))
+	redaction := stacktrace.WithRedactionPolicy(stacktrace.DefaultRedactionPolicy())
+	for _, log := range []func(logger *slog.Logger){
+		func(logger *slog.Logger) { (&Reporter{Tree: true}).Log(context.Background(), logger, err, redaction) },
+		func(logger *slog.Logger) { Log(context.Background(), logger, err, redaction) },
+	} {
+		var buf bytes.Buffer
+		log(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})))
+		got := buf.String()
+		if strings.Contains(got, "hunter2") {
+			t.Errorf("Log() = %s, must not contain the secret", got)
+		}
+		if !strings.Contains(got, `"msg":"login failed: password: [REDACTED]"`) {
+			t.Errorf("Log() = %s, want the redacted message", got)
+		}
+		if !strings.Contains(got, "level_test.go") {
+			t.Errorf("Log() = %s, want the source in level_test.go", got)
+		}
+	}
+}
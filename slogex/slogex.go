@@ -1,59 +1,41 @@
 package slogex
 
 import (
-	"fmt"
 	"log/slog"
+	"sort"
 
 	"github.com/acronis/go-stacktrace"
 )
 
+// ErrToSlogAttr returns the attribute of the error laid out by the DefaultLayout.
 func ErrToSlogAttr(err error, opts ...stacktrace.TracesOpt) slog.Attr {
-	if err == nil {
-		return slog.Attr{}
-	}
+	return DefaultLayout.Attr(err, opts...)
+}
 
-	st, ok := stacktrace.Unwrap(err)
-	if !ok {
-		return slog.String("error", err.Error())
+// argsAttr returns the group of the message template arguments sorted by name.
+func argsAttr(key string, args stacktrace.Args) slog.Attr {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
 	}
-
-	if st == nil {
-		return slog.String("error", "nil stacktrace")
+	sort.Strings(names)
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, slog.Any(name, args[name]))
 	}
+	return slog.Group(key, attrs...)
+}
 
-	tracebacks := st.GetTraces(opts...)
-
-	tracebackWidth := len(fmt.Sprintf("%d", len(tracebacks)))
-	tracebackAttrs := make([]slog.Attr, 0, len(tracebacks))
-	for traceIndex := range tracebacks {
-		traceback := tracebacks[traceIndex]
-		stackWidth := len(fmt.Sprintf("%d", len(traceback.Stack)))
-		stackAttrs := make([]slog.Attr, 0, len(traceback.Stack))
-		for stackIndex := range traceback.Stack {
-			stack := traceback.Stack[stackIndex]
-			key := fmt.Sprintf("%0*d", stackWidth, stackIndex)
-			stackAttr := slog.Group(
-				key,
-				func() []any {
-					attrs := []any{}
-					if stack.Type != nil {
-						attrs = append(attrs, slog.String("type", stack.Type.String()))
-					}
-					if stack.Severity != nil {
-						attrs = append(attrs, slog.String("severity", stack.Severity.String()))
-					}
-					if stack.LinePos != nil {
-						attrs = append(attrs, slog.String("position", *stack.LinePos))
-					}
-					attrs = append(attrs, slog.String("message", stack.Message))
-					return attrs
-				}()...,
-			)
-			stackAttrs = append(stackAttrs, stackAttr)
-		}
-		tracebackAttr := slog.Group(fmt.Sprintf("%0*d", tracebackWidth, traceIndex), "stack", stackAttrs)
-		tracebackAttrs = append(tracebackAttrs, tracebackAttr)
+// infoAttr returns the group of the info entries sorted by key.
+func infoAttr(key string, info map[string]string) slog.Attr {
+	keys := make([]string, 0, len(info))
+	for k := range info {
+		keys = append(keys, k)
 	}
-
-	return slog.Group("tracebacks", "traces", tracebackAttrs)
+	sort.Strings(keys)
+	attrs := make([]any, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.String(k, info[k]))
+	}
+	return slog.Group(key, attrs...)
 }
@@ -45,13 +45,97 @@ func TestErrToSlogAttr(t *testing.T) {
 				},
 			),
 		},
+		{
+			name: "Test with code",
+			args: args{
+				err: stacktrace.New("error message",
+					stacktrace.WithLocation("location.raml"),
+					stacktrace.WithCode("RAML1023"),
+				),
+				opts: []stacktrace.TracesOpt{},
+			},
+			want: slog.Group(
+				"tracebacks", "traces", []slog.Attr{
+					slog.Group(
+						"0", "stack", []slog.Attr{
+							slog.Group(
+								"0",
+								slog.String("code", "RAML1023"),
+								slog.String("position", "location.raml:1"),
+								slog.String("message", "error message"),
+							),
+						},
+					),
+				},
+			),
+		},
+		{
+			name: "Test with template",
+			args: args{
+				err: stacktrace.NewT("property {name} is not allowed", stacktrace.Args{"name": "baseUri", "count": 2},
+					stacktrace.WithLocation("location.raml"),
+				),
+				opts: []stacktrace.TracesOpt{},
+			},
+			want: slog.Group(
+				"tracebacks", "traces", []slog.Attr{
+					slog.Group(
+						"0", "stack", []slog.Attr{
+							slog.Group(
+								"0",
+								slog.String("position", "location.raml:1"),
+								slog.String("message", "property baseUri is not allowed"),
+								slog.String("template", "property {name} is not allowed"),
+								slog.Group("args", slog.Int("count", 2), slog.String("name", "baseUri")),
+							),
+						},
+					),
+				},
+			),
+		},
+		{
+			name: "Test with secret",
+			args: args{
+				err: stacktrace.NewT("login of {user} failed", stacktrace.Args{"user": stacktrace.NewSecret("alice")},
+					stacktrace.WithInfo("password", stacktrace.NewSecret("p4ss")),
+				),
+				opts: []stacktrace.TracesOpt{},
+			},
+			want: slog.Group(
+				"tracebacks", "traces", []slog.Attr{
+					slog.Group(
+						"0", "stack", []slog.Attr{
+							slog.Group(
+								"0",
+								slog.String("message", "login of [REDACTED] failed"),
+								slog.Group("info", slog.String("password", "[REDACTED]")),
+								slog.String("template", "login of {user} failed"),
+								slog.Group("args", slog.String("user", "[REDACTED]")),
+							),
+						},
+					),
+				},
+			),
+		},
 		{
 			name: "Test is not a stacktrace",
 			args: args{
 				err:  fmt.Errorf("error message"),
 				opts: []stacktrace.TracesOpt{},
 			},
-			want: slog.String("error", "error message"),
+			want: slog.Group(
+				"tracebacks", "traces", []slog.Attr{
+					slog.Group(
+						"0", "stack", []slog.Attr{
+							slog.Group(
+								"0",
+								slog.String("type", "*errors.errorString"),
+								slog.String("message", "error message"),
+							),
+						},
+					),
+				},
+			),
 		},
 		{
 			name: "Test nil err",
@@ -18,6 +18,17 @@ func (t *Type) String() string {
 	return string(*t)
 }
 
+// Code is the machine-stable code of the error, e.g. "RAML1023".
+type Code string
+
+// String is a fmt.Stringer implementation.
+func (c *Code) String() string {
+	if c == nil {
+		return ""
+	}
+	return string(*c)
+}
+
 // Severity is the severity of the error.
 type Severity string
 
@@ -92,6 +103,27 @@ func (s *StructInfo) String() string {
 	return result
 }
 
+// RedactedString returns a string representation of the struct info with the sensitive values redacted.
+func (s *StructInfo) RedactedString(p *RedactionPolicy) string {
+	segs := make([]string, 0, len(s.info))
+	for _, k := range s.SortedKeys() {
+		segs = append(segs, fmt.Sprintf("%s: %s", k, p.RedactValue(k, s.info[k])))
+	}
+	return strings.Join(segs, ": ")
+}
+
+// redactedMap returns the entries with the values redacted by the policy, nil if there are no entries.
+func (s *StructInfo) redactedMap(p *RedactionPolicy) map[string]string {
+	if len(s.info) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(s.info))
+	for k, v := range s.info {
+		result[k] = p.RedactValue(k, v)
+	}
+	return result
+}
+
 // ensureMap ensures that the map is initialized.
 func (s *StructInfo) ensureMap() {
 	if s.info == nil {
@@ -181,10 +213,14 @@ type StackTrace struct {
 	Severity *Severity
 	// Type is the type of the error.
 	Type *Type
+	// Code is the machine-stable code of the error.
+	Code *Code
 	// Location is the location file path of the error.
 	Location *Location
 	// Position is the position of the error in the file.
 	Position *Position
+	// HTTPStatus is the HTTP status of the error, zero if not set.
+	HTTPStatus int
 
 	// Wrapped is the error that wrapped by this error.
 	Wrapped *StackTrace
@@ -192,6 +228,10 @@ type StackTrace struct {
 	Err error
 	// Message is the error message.
 	Message string
+	// Template is the message template with named parameters the message is rendered from.
+	Template string
+	// Args are the arguments of the message template.
+	Args Args
 	// Info is the additional information about the error.
 	Info StructInfo
 
@@ -205,6 +245,9 @@ type StackTrace struct {
 func (st *StackTrace) Header() string {
 	segs := make([]string, 0)
 	result := st.Type.String()
+	if st.Code != nil && *st.Code != "" {
+		result = fmt.Sprintf("%s[%s]", result, st.Code)
+	}
 	if result != "" {
 		segs = append(segs, result)
 	}
@@ -222,12 +265,22 @@ type Option interface {
 
 // OrigString returns the original error message without the wrapping messages.
 func (st *StackTrace) OrigString() string {
+	return st.origString(nil)
+}
+
+// OrigStringL returns the error message without the wrapping messages translated by the given localizer.
+func (st *StackTrace) OrigStringL(l Localizer) string {
+	return st.origString(l)
+}
+
+// origString returns the error message without the wrapping messages translated by the given localizer.
+func (st *StackTrace) origString(l Localizer) string {
 	segs := make([]string, 0)
 	header := st.Header()
 	if header != "" {
 		segs = append(segs, header)
 	}
-	msg := st.MessageWithInfo()
+	msg := st.messageWithInfo(l, currentRedactionPolicy())
 	if msg != "" {
 		segs = append(segs, msg)
 	}
@@ -247,13 +300,27 @@ func (st *StackTrace) OrigStringW() string {
 	return strings.Join(segs, ": ")
 }
 
+// MessageWithInfo returns the message with the additional information.
+// The sensitive data is redacted by the policy set by SetRedactionPolicy.
 func (st *StackTrace) MessageWithInfo() string {
+	return st.messageWithInfo(nil, currentRedactionPolicy())
+}
+
+// text returns the message translated by the given localizer and redacted by the given policy,
+// without the additional information.
+func (st *StackTrace) text(l Localizer, p *RedactionPolicy) string {
+	return p.RedactString(st.localizedMessage(l, p))
+}
+
+// messageWithInfo returns the message translated by the given localizer with the additional information
+// redacted by the given policy.
+func (st *StackTrace) messageWithInfo(l Localizer, p *RedactionPolicy) string {
 	segments := make([]string, 0)
-	if st.Message != "" {
-		segments = append(segments, st.Message)
+	if msg := st.text(l, p); msg != "" {
+		segments = append(segments, msg)
 	}
 	if len(st.Info.info) > 0 {
-		segments = append(segments, st.Info.String())
+		segments = append(segments, st.Info.RedactedString(p))
 	}
 	return strings.Join(segments, ": ")
 }
@@ -261,13 +328,24 @@ func (st *StackTrace) MessageWithInfo() string {
 // String implements the fmt.Stringer interface.
 // It returns the string representation of the StackTrace.
 func (st *StackTrace) String() string {
+	return st.string(nil)
+}
+
+// StringL returns the string representation of the StackTrace with the messages translated by the given localizer.
+// The Error method is not localized.
+func (st *StackTrace) StringL(l Localizer) string {
+	return st.string(l)
+}
+
+// string returns the string representation of the StackTrace with the messages translated by the given localizer.
+func (st *StackTrace) string(l Localizer) string {
 	segs := make([]string, 0)
-	orig := st.OrigString()
+	orig := st.origString(l)
 	if orig != "" {
 		segs = append(segs, orig)
 	}
 	if st.Wrapped != nil {
-		segs = append(segs, st.Wrapped.String())
+		segs = append(segs, st.Wrapped.string(l))
 	}
 
 	res := strings.Join(segs, ": ")
@@ -276,7 +354,7 @@ func (st *StackTrace) String() string {
 		lists := make([]string, 0)
 		lists = append(lists, res)
 		for _, elem := range st.List {
-			lists = append(lists, elem.String())
+			lists = append(lists, elem.string(l))
 		}
 		res = strings.Join(lists, "; ")
 	}
@@ -305,8 +383,7 @@ func Unwrap(err error) (*StackTrace, bool) {
 		}
 		wrapped, ok = Unwrap(errWrapped)
 		if ok && wrapped != nil {
-			msg, _, _ := strings.Cut(err.Error(), wrapped.Message)
-			msg = strings.TrimSuffix(msg, ": ")
+			msg := strings.TrimSuffix(wrapperMessage(err.Error(), wrapped), ": ")
 			wrapped = New(msg).Wrap(wrapped)
 			wrapped.Err = err
 		}
@@ -318,6 +395,20 @@ func Unwrap(err error) (*StackTrace, bool) {
 	return wrapped, ok
 }
 
+// wrapperMessage returns the text of the error wrapping the StackTrace before the wrapped message.
+// The wrapped message is searched as is, then redacted by the policy set by SetRedactionPolicy,
+// as the text of the wrapping error embeds the redacted Error of the StackTrace.
+func wrapperMessage(text string, wrapped *StackTrace) string {
+	if msg, _, ok := strings.Cut(text, wrapped.Message); ok {
+		return msg
+	}
+	p := currentRedactionPolicy()
+	if msg, _, ok := strings.Cut(text, p.RedactString(wrapped.localizedMessage(nil, p))); ok {
+		return msg
+	}
+	return text
+}
+
 // Is checks if the given error is the same as the StackTrace.
 func (st *StackTrace) Is(err error) bool {
 	if st == nil {
@@ -326,6 +417,9 @@ func (st *StackTrace) Is(err error) bool {
 	if err == nil {
 		return false
 	}
+	if sentinel, ok := err.(*Sentinel); ok {
+		return sentinel.match(st)
+	}
 	wrapped, ok := Unwrap(err)
 	if !ok {
 		return false
@@ -388,6 +482,34 @@ func (o optErrType) Apply(e *StackTrace) {
 	_ = e.SetType(o.ErrType)
 }
 
+type optErrCode struct {
+	Code    Code
+	Catalog *Catalog
+}
+
+func (o optErrCode) Apply(e *StackTrace) {
+	e.Code = &o.Code
+	entry, ok := o.Catalog.Lookup(o.Code)
+	if !ok {
+		return
+	}
+	if e.Severity == nil && entry.Severity != "" {
+		severity := entry.Severity
+		e.Severity = &severity
+	}
+	if !e.typeIsSet && entry.Type != "" {
+		_ = e.SetType(entry.Type)
+	}
+}
+
+type optErrHTTPStatus struct {
+	Status int
+}
+
+func (o optErrHTTPStatus) Apply(e *StackTrace) {
+	e.HTTPStatus = o.Status
+}
+
 func WithInfo(key string, value any) Option {
 	return optErrInfo{Key: key, Value: Stringer(value)}
 }
@@ -405,6 +527,18 @@ func WithType(errType Type) Option {
 	return optErrType{ErrType: errType}
 }
 
+// WithCode sets the code of the error.
+// If the code is registered in the DefaultCatalog, the severity and the type
+// of the error are inherited from the catalog entry unless they are already set.
+func WithCode(code Code) Option {
+	return optErrCode{Code: code, Catalog: DefaultCatalog}
+}
+
+// WithHTTPStatus sets the HTTP status of the error.
+func WithHTTPStatus(status int) Option {
+	return optErrHTTPStatus{Status: status}
+}
+
 // WithLocation sets the location of the error.
 func WithLocation(location string) Option {
 	return optErrLocation{Location: Location(location)}
@@ -451,6 +585,18 @@ func (st *StackTrace) SetType(t Type) *StackTrace {
 	return st
 }
 
+// SetCode sets the code of the StackTrace and returns it
+func (st *StackTrace) SetCode(code Code) *StackTrace {
+	st.Code = &code
+	return st
+}
+
+// SetHTTPStatus sets the HTTP status of the StackTrace and returns it
+func (st *StackTrace) SetHTTPStatus(status int) *StackTrace {
+	st.HTTPStatus = status
+	return st
+}
+
 // SetLocation sets the location of the StackTrace and returns it
 func (st *StackTrace) SetLocation(location string) *StackTrace {
 	loc := Location(location)
@@ -467,6 +613,8 @@ func (st *StackTrace) SetPosition(pos *Position) *StackTrace {
 // SetMessage sets the message of the StackTrace and returns it
 func (st *StackTrace) SetMessage(message string, a ...any) *StackTrace {
 	st.Message = fmt.Sprintf(message, a...)
+	st.Template = ""
+	st.Args = nil
 	return st
 }
 
@@ -459,6 +459,37 @@ func TestError_SetSeverity(t *testing.T) {
 	}
 }
 
+func TestError_SetHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		st   *StackTrace
+		want int
+	}{
+		{
+			name: "Check option",
+			st:   New("not found", WithHTTPStatus(404)),
+			want: 404,
+		},
+		{
+			name: "Check set HTTP status",
+			st:   New("not found").SetHTTPStatus(410),
+			want: 410,
+		},
+		{
+			name: "Check unset",
+			st:   New("not found"),
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.st.HTTPStatus; got != tt.want {
+				t.Errorf("HTTPStatus = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestError_SetType(t *testing.T) {
 	type fields struct {
 		ErrType Type
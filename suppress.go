@@ -0,0 +1,335 @@
+package stacktrace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SuppressionRule silences the diagnostics matching all its non-empty conditions.
+type SuppressionRule struct {
+	// Type matches the type of the diagnostic.
+	Type Type `json:"type,omitempty"`
+	// Code matches the code of the diagnostic.
+	Code Code `json:"code,omitempty"`
+	// Location is a glob matching the location of the diagnostic.
+	// "*" matches any sequence of characters except "/", "**" matches any sequence of characters.
+	Location string `json:"location,omitempty"`
+	// Message is a regular expression matching the original message of the diagnostic or its template.
+	// The message is matched before the localization and the redaction, without the Info.
+	Message string `json:"message,omitempty"`
+	// Expires is the time after which the rule is no longer applied, zero means never.
+	Expires time.Time `json:"expires,omitempty"`
+	// Reason is the explanation of the suppression.
+	Reason string `json:"reason,omitempty"`
+
+	location *regexp.Regexp
+	message  *regexp.Regexp
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The expiry date can be given as a date, e.g. "2025-12-31", or as an RFC 3339 time.
+func (r *SuppressionRule) UnmarshalJSON(data []byte) error {
+	type rule SuppressionRule
+	var v struct {
+		rule
+		Expires string `json:"expires,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = SuppressionRule(v.rule)
+	if v.Expires == "" {
+		return nil
+	}
+	expires, err := time.Parse(time.RFC3339, v.Expires)
+	if err != nil {
+		date, dateErr := time.Parse(time.DateOnly, v.Expires)
+		if dateErr != nil {
+			return fmt.Errorf("invalid expires %q: %w", v.Expires, err)
+		}
+		// the rule applies during the whole expiry date
+		expires = date.AddDate(0, 0, 1)
+	}
+	r.Expires = expires
+	return nil
+}
+
+// compile compiles the patterns of the rule.
+func (r *SuppressionRule) compile() error {
+	if r.Type == "" && r.Code == "" && r.Location == "" && r.Message == "" {
+		return errors.New("rule has no conditions")
+	}
+	if r.Location != "" {
+		re, err := compileGlob(r.Location)
+		if err != nil {
+			return fmt.Errorf("location: %w", err)
+		}
+		r.location = re
+	}
+	if r.Message != "" {
+		re, err := regexp.Compile(r.Message)
+		if err != nil {
+			return fmt.Errorf("message: %w", err)
+		}
+		r.message = re
+	}
+	return nil
+}
+
+// expired checks if the rule is expired at the given time.
+func (r *SuppressionRule) expired(now time.Time) bool {
+	return !r.Expires.IsZero() && !now.Before(r.Expires)
+}
+
+// match checks if the stack entry matches all conditions of the rule.
+func (r *SuppressionRule) match(stack *Stack) bool {
+	if r.Type != "" && (stack.Type == nil || *stack.Type != r.Type) {
+		return false
+	}
+	if r.Code != "" && (stack.Code == nil || *stack.Code != r.Code) {
+		return false
+	}
+	if r.location != nil && (stack.Location == nil || !r.location.MatchString(stack.Location.String())) {
+		return false
+	}
+	if r.message != nil && !r.matchMessage(stack) {
+		return false
+	}
+	return true
+}
+
+// matchMessage checks if the original message or the template of the stack entry matches the message pattern.
+// The message of the stack entry is used for the entries not built from a StackTrace.
+func (r *SuppressionRule) matchMessage(stack *Stack) bool {
+	return r.message.MatchString(stack.originalMessage()) || (stack.Template != "" && r.message.MatchString(stack.Template))
+}
+
+// compileGlob converts the location glob to a regular expression.
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**") {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// Suppressions is a set of suppression rules.
+type Suppressions struct {
+	rules []*SuppressionRule
+	// Now returns the current time used to check the expiry of the rules, defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewSuppressions creates a new set of the given suppression rules.
+func NewSuppressions(rules ...SuppressionRule) (*Suppressions, error) {
+	s := &Suppressions{
+		rules: make([]*SuppressionRule, 0, len(rules)),
+	}
+	for i := range rules {
+		rule := rules[i]
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("suppression rule %d: %w", i, err)
+		}
+		s.rules = append(s.rules, &rule)
+	}
+	return s, nil
+}
+
+// LoadSuppressions loads the suppression rules from the JSON file with the given name from fsys.
+// The file is a JSON array of suppression rules.
+func LoadSuppressions(fsys fs.FS, name string) (*Suppressions, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("read suppressions: %w", err)
+	}
+	var rules []SuppressionRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("decode suppressions %s: %w", name, err)
+	}
+	return NewSuppressions(rules...)
+}
+
+// Rules returns the suppression rules.
+func (s *Suppressions) Rules() []SuppressionRule {
+	result := make([]SuppressionRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		result = append(result, *rule)
+	}
+	return result
+}
+
+// Suppressed is a diagnostic silenced by a suppression rule.
+type Suppressed struct {
+	// Rule is the rule silencing the diagnostic.
+	Rule SuppressionRule
+	// Trace is the silenced diagnostic.
+	Trace Trace
+}
+
+// SuppressionReport describes the result of the suppression.
+type SuppressionReport struct {
+	// Suppressed are the silenced diagnostics.
+	Suppressed []Suppressed
+	// Expired are the rules which were not applied because they are expired.
+	Expired []SuppressionRule
+	// Unused are the active rules which did not match any diagnostic.
+	Unused []SuppressionRule
+}
+
+// suppression is the state of one application of the suppressions.
+type suppression struct {
+	active []*SuppressionRule
+	used   map[*SuppressionRule]struct{}
+	report SuppressionReport
+}
+
+// begin starts a new application of the suppressions.
+func (s *Suppressions) begin() *suppression {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	sp := &suppression{
+		active: make([]*SuppressionRule, 0, len(s.rules)),
+		used:   make(map[*SuppressionRule]struct{}),
+	}
+	for _, rule := range s.rules {
+		if rule.expired(now()) {
+			sp.report.Expired = append(sp.report.Expired, *rule)
+			continue
+		}
+		sp.active = append(sp.active, rule)
+	}
+	return sp
+}
+
+// match returns the first active rule matching any entry of the stack.
+func (sp *suppression) match(stack []Stack) *SuppressionRule {
+	for _, rule := range sp.active {
+		for i := range stack {
+			if rule.match(&stack[i]) {
+				sp.used[rule] = struct{}{}
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// end finishes the application of the suppressions and returns the report.
+func (sp *suppression) end() SuppressionReport {
+	for _, rule := range sp.active {
+		if _, ok := sp.used[rule]; !ok {
+			sp.report.Unused = append(sp.report.Unused, *rule)
+		}
+	}
+	return sp.report
+}
+
+// ApplyTraces returns the traces not matching any rule and the report of the suppressed ones.
+// A trace matches a rule if any of its stack entries matches it.
+func (s *Suppressions) ApplyTraces(traces []Trace) ([]Trace, SuppressionReport) {
+	sp := s.begin()
+	kept := make([]Trace, 0, len(traces))
+	for _, trace := range traces {
+		if rule := sp.match(trace.Stack); rule != nil {
+			sp.report.Suppressed = append(sp.report.Suppressed, Suppressed{Rule: *rule, Trace: trace})
+			continue
+		}
+		kept = append(kept, trace)
+	}
+	return kept, sp.end()
+}
+
+// Apply returns a copy of the StackTrace without the nodes matching any rule and the report of the suppressed ones.
+// The nodes are matched as the traces by ApplyTraces, i.e. a node emitting a trace is suppressed if any entry of
+// its trace matches a rule, and a wrapping node is removed if all its wrapped traces are suppressed. The lists of
+// the removed nodes are kept in their place, and the containers whose lists are entirely suppressed are removed.
+// So the traces of the result are the traces kept by ApplyTraces. It returns nil if all the traces are suppressed.
+// The StackTrace is not modified.
+func (s *Suppressions) Apply(st *StackTrace, opts ...TracesOpt) (*StackTrace, SuppressionReport) {
+	o := NewTracesOptions()
+	for _, opt := range opts {
+		opt.Apply(o)
+	}
+	sp := s.begin()
+	var kept *StackTrace
+	if st != nil {
+		kept = joinKept(sp.apply(st, nil, o))
+	}
+	return kept, sp.end()
+}
+
+// apply returns the copies of the StackTrace without the suppressed nodes, with the given stack entries of the
+// wrapping nodes. It returns the kept elements of the list in place of the StackTrace if it is removed.
+func (sp *suppression) apply(st *StackTrace, ancestors []Stack, opts *TracesOptions) []*StackTrace {
+	path := make([]Stack, len(ancestors), len(ancestors)+1)
+	copy(path, ancestors)
+	path = append(path, *st.newStack(opts))
+
+	list := make([]*StackTrace, 0, len(st.List))
+	for _, elem := range st.List {
+		list = append(list, sp.apply(elem, ancestors, opts)...)
+	}
+
+	kept := *st
+	kept.List = nil
+	removed := false
+	switch {
+	case st.Wrapped != nil:
+		wrapped := sp.apply(st.Wrapped, path, opts)
+		kept.Wrapped = joinKept(wrapped)
+		removed = len(wrapped) == 0
+	case path[len(path)-1].LinePos != nil || len(st.List) == 0:
+		if rule := sp.match(path); rule != nil {
+			trace := Trace{Stack: path}
+			if opts.Fingerprint != nil {
+				trace.Fingerprint = traceFingerprint(&trace, opts.Fingerprint)
+			}
+			sp.report.Suppressed = append(sp.report.Suppressed, Suppressed{Rule: *rule, Trace: trace})
+			removed = true
+		}
+	default:
+		// the container does not emit a trace itself, it is removed with its last list element
+		removed = len(list) == 0
+	}
+	if removed {
+		return list
+	}
+	for _, elem := range list {
+		kept.Append(elem)
+	}
+	return []*StackTrace{&kept}
+}
+
+// joinKept returns the only kept StackTrace, or a container without a location of the kept StackTraces,
+// nil if there are none.
+func joinKept(kept []*StackTrace) *StackTrace {
+	switch len(kept) {
+	case 0:
+		return nil
+	case 1:
+		return kept[0]
+	default:
+		return &StackTrace{List: kept}
+	}
+}
@@ -0,0 +1,214 @@
+package stacktrace
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSuppressions_ApplyTraces(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	st := New("validation failed").
+		Append(New("property is not allowed", WithType("unknown-property"), WithLocation("specs/legacy/api.raml"))).
+		Append(New("property is not allowed", WithType("unknown-property"), WithLocation("specs/api.raml"))).
+		Append(New("unexpected token", WithType("parsing"), WithCode("RAML0001"), WithLocation("specs/api.raml"))).
+		Append(New("deprecated field", WithType("deprecation"), WithLocation("specs/api.raml")))
+
+	tests := []struct {
+		name        string
+		rules       []SuppressionRule
+		wantKept    []string
+		wantCount   int
+		wantExpired int
+		wantUnused  int
+	}{
+		{
+			name:      "Check location glob",
+			rules:     []SuppressionRule{{Type: "unknown-property", Location: "specs/legacy/**"}},
+			wantKept:  []string{"specs/api.raml:1", "specs/api.raml:1", "specs/api.raml:1"},
+			wantCount: 1,
+		},
+		{
+			name:      "Check code and message",
+			rules:     []SuppressionRule{{Code: "RAML0001"}, {Message: "^deprecated"}},
+			wantKept:  []string{"specs/legacy/api.raml:1", "specs/api.raml:1"},
+			wantCount: 2,
+		},
+		{
+			name: "Check expired and unused",
+			rules: []SuppressionRule{
+				{Type: "parsing", Expires: now.Add(-time.Hour)},
+				{Type: "unknown"},
+			},
+			wantKept:    []string{"specs/legacy/api.raml:1", "specs/api.raml:1", "specs/api.raml:1", "specs/api.raml:1"},
+			wantExpired: 1,
+			wantUnused:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSuppressions(tt.rules...)
+			if err != nil {
+				t.Fatalf("NewSuppressions() error = %v", err)
+			}
+			s.Now = func() time.Time { return now }
+			kept, report := s.ApplyTraces(st.GetTraces())
+			got := make([]string, 0, len(kept))
+			for _, trace := range kept {
+				got = append(got, *trace.Stack[0].LinePos)
+			}
+			if !reflect.DeepEqual(got, tt.wantKept) {
+				t.Errorf("ApplyTraces() kept = %v, want %v", got, tt.wantKept)
+			}
+			if len(report.Suppressed) != tt.wantCount || len(report.Expired) != tt.wantExpired || len(report.Unused) != tt.wantUnused {
+				t.Errorf("ApplyTraces() report = %+v", report)
+			}
+		})
+	}
+}
+
+func TestSuppressions_ApplyTracesRawMessage(t *testing.T) {
+	bundle := &Bundle{Locale: "de", Messages: map[string]string{
+		"file not found":                 "Datei nicht gefunden",
+		"property {name} is not allowed": "Eigenschaft {name} ist nicht erlaubt",
+	}}
+	policy, err := NewRedactionPolicy(nil, []string{`secret\w*`})
+	if err != nil {
+		t.Fatalf("NewRedactionPolicy() error = %v", err)
+	}
+	st := New("validation failed").
+		Append(New("file not found", WithLocation("a.raml"), WithInfo("path", "a.raml"))).
+		Append(NewT("property {name} is not allowed", Args{"name": "secretKey"}, WithLocation("b.raml")))
+
+	tests := []struct {
+		name string
+		rule SuppressionRule
+	}{
+		{name: "Check localized message", rule: SuppressionRule{Message: "^file not found$"}},
+		{name: "Check redacted message", rule: SuppressionRule{Message: "property secretKey is"}},
+		{name: "Check template", rule: SuppressionRule{Message: "^property {name} is not allowed$"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSuppressions(tt.rule)
+			if err != nil {
+				t.Fatalf("NewSuppressions() error = %v", err)
+			}
+			kept, report := s.ApplyTraces(st.GetTraces(WithLocalizer(bundle), WithRedactionPolicy(policy)))
+			if len(kept) != 1 || len(report.Suppressed) != 1 {
+				t.Errorf("ApplyTraces() kept = %v, suppressed = %v", kept, report.Suppressed)
+			}
+		})
+	}
+}
+
+func TestSuppressions_Apply(t *testing.T) {
+	st := New("validation failed").
+		Append(NewWrapped("in library", New("property is not allowed", WithType("unknown-property"), WithLocation("lib.raml")))).
+		Append(New("unexpected token", WithType("parsing"), WithLocation("api.raml")))
+
+	s, err := NewSuppressions(SuppressionRule{Type: "unknown-property", Reason: "known issue"})
+	if err != nil {
+		t.Fatalf("NewSuppressions() error = %v", err)
+	}
+	kept, report := s.Apply(st)
+	if got := kept.Error(); got != "validation failed; parsing: api.raml:1: unexpected token" {
+		t.Errorf("Apply() = %v", got)
+	}
+	if len(st.List) != 2 {
+		t.Errorf("Apply() must not modify the StackTrace")
+	}
+	if len(report.Suppressed) != 1 || report.Suppressed[0].Rule.Reason != "known issue" || len(report.Suppressed[0].Trace.Stack) != 2 {
+		t.Errorf("Apply() report = %+v", report)
+	}
+
+	if kept, _ := s.Apply(New("message", WithType("unknown-property"))); kept != nil {
+		t.Errorf("Apply() = %v, want nil", kept)
+	}
+}
+
+func TestSuppressions_ApplyMatchesApplyTraces(t *testing.T) {
+	container := func() *StackTrace {
+		return New("").
+			Append(New("property x is not allowed", WithType("unknown-property"), WithLocation("a.raml"))).
+			Append(New("invalid value", WithType("validating"), WithLocation("b.raml")))
+	}
+	tests := []struct {
+		name string
+		st   *StackTrace
+		kept int
+	}{
+		{
+			name: "Check wrapped container",
+			st:   New("validation failed").Wrap(container()),
+			kept: 1,
+		},
+		{
+			name: "Check wrapped list",
+			st: New("validation failed").Wrap(New("reading failed", WithLocation("api.raml")).
+				Wrap(New("property y is not allowed", WithType("unknown-property"), WithLocation("c.raml"))).
+				Append(New("unexpected token", WithType("parsing"), WithLocation("d.raml")))),
+			kept: 1,
+		},
+		{
+			name: "Check list of wrapped nodes",
+			st: New("validation failed").
+				Append(New("in library").Wrap(container())).
+				Append(New("unexpected token", WithType("parsing"), WithLocation("d.raml"))),
+			kept: 2,
+		},
+		{
+			name: "Check all suppressed",
+			st:   New("validation failed").Wrap(New("property x is not allowed", WithType("unknown-property"))),
+			kept: 0,
+		},
+	}
+	s, err := NewSuppressions(SuppressionRule{Type: "unknown-property"})
+	if err != nil {
+		t.Fatalf("NewSuppressions() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wantKept, wantReport := s.ApplyTraces(tt.st.GetTraces())
+			kept, report := s.Apply(tt.st)
+			var gotKept []Trace
+			if kept != nil {
+				gotKept = kept.GetTraces()
+			}
+			if len(wantKept) != tt.kept || len(gotKept) != len(wantKept) || (len(wantKept) > 0 && !reflect.DeepEqual(gotKept, wantKept)) {
+				t.Errorf("Apply() kept = %v, ApplyTraces() kept = %v", gotKept, wantKept)
+			}
+			if !reflect.DeepEqual(report, wantReport) {
+				t.Errorf("Apply() report = %+v, ApplyTraces() report = %+v", report, wantReport)
+			}
+		})
+	}
+}
+
+func TestLoadSuppressions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"suppressions.json": &fstest.MapFile{Data: []byte(`[
+			{"type": "unknown-property", "location": "legacy/*.raml", "expires": "2025-12-31", "reason": "migration"},
+			{"message": "deprecated", "expires": "2025-12-31T10:00:00Z"}
+		]`)},
+		"invalid.json": &fstest.MapFile{Data: []byte(`[{"reason": "no conditions"}]`)},
+	}
+	s, err := LoadSuppressions(fsys, "suppressions.json")
+	if err != nil {
+		t.Fatalf("LoadSuppressions() error = %v", err)
+	}
+	rules := s.Rules()
+	if len(rules) != 2 {
+		t.Fatalf("Rules() = %v", rules)
+	}
+	if want := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); !rules[0].Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", rules[0].Expires, want)
+	}
+	if want := time.Date(2025, 12, 31, 10, 0, 0, 0, time.UTC); !rules[1].Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", rules[1].Expires, want)
+	}
+	if _, err := LoadSuppressions(fsys, "invalid.json"); err == nil {
+		t.Errorf("LoadSuppressions() expected error for rule without conditions")
+	}
+}
@@ -0,0 +1,90 @@
+package stacktrace
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Args are the named arguments of a message template.
+type Args map[string]any
+
+// scanTemplate scans the message template calling literal for the literal text
+// and param for every named parameter. Braces are escaped by doubling them.
+func scanTemplate(template string, literal func(string), param func(string)) error {
+	for len(template) > 0 {
+		i := strings.IndexAny(template, "{}")
+		if i < 0 {
+			literal(template)
+			return nil
+		}
+		literal(template[:i])
+		switch {
+		case strings.HasPrefix(template[i:], "{{"):
+			literal("{")
+			template = template[i+2:]
+		case strings.HasPrefix(template[i:], "}}"):
+			literal("}")
+			template = template[i+2:]
+		case template[i] == '}':
+			literal("}")
+			template = template[i+1:]
+		default:
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				literal(template[i:])
+				return fmt.Errorf("unterminated parameter in template %q", template[i:])
+			}
+			param(template[i+1 : i+end])
+			template = template[i+end+1:]
+		}
+	}
+	return nil
+}
+
+// RenderTemplate renders the message template replacing the named parameters, e.g. "{name}",
+// with the string representation of the arguments. Parameters without an argument are kept as is.
+func RenderTemplate(template string, args Args) string {
+	var b strings.Builder
+	_ = scanTemplate(template, func(s string) {
+		b.WriteString(s)
+	}, func(name string) {
+		v, ok := args[name]
+		if !ok {
+			b.WriteString("{" + name + "}")
+			return
+		}
+		b.WriteString(Stringer(v).String())
+	})
+	return b.String()
+}
+
+// TemplateParams returns the names of the template parameters in the order of their first appearance.
+func TemplateParams(template string) ([]string, error) {
+	names := make([]string, 0)
+	seen := make(map[string]struct{})
+	err := scanTemplate(template, func(string) {}, func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// NewT creates a new StackTrace with the message rendered from the template and the arguments.
+// The template and the arguments are kept for the machine consumers.
+func NewT(template string, args Args, opts ...Option) *StackTrace {
+	return New("", opts...).SetMessageT(template, args)
+}
+
+// SetMessageT sets the message template and the arguments of the StackTrace and returns it
+func (st *StackTrace) SetMessageT(template string, args Args) *StackTrace {
+	st.Message = RenderTemplate(template, args)
+	st.Template = template
+	st.Args = args
+	return st
+}
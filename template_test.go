@@ -0,0 +1,103 @@
+package stacktrace
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		args     Args
+		want     string
+	}{
+		{
+			name:     "Check parameters",
+			template: "property {name} is not allowed in {type}",
+			args:     Args{"name": "baseUri", "type": "Object"},
+			want:     "property baseUri is not allowed in Object",
+		},
+		{
+			name:     "Check non string arguments",
+			template: "expected {count} items, strict: {strict}",
+			args:     Args{"count": 3, "strict": true},
+			want:     "expected 3 items, strict: true",
+		},
+		{
+			name:     "Check missing argument",
+			template: "property {name} is not allowed",
+			args:     Args{},
+			want:     "property {name} is not allowed",
+		},
+		{
+			name:     "Check escapes",
+			template: "{{name}} is {name}}}",
+			args:     Args{"name": "value"},
+			want:     "{name} is value}",
+		},
+		{
+			name:     "Check unterminated parameter",
+			template: "property {name",
+			args:     Args{"name": "value"},
+			want:     "property {name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderTemplate(tt.template, tt.args); got != tt.want {
+				t.Errorf("RenderTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "Check parameters",
+			template: "{name} is not allowed in {type}, {name}",
+			want:     []string{"name", "type"},
+		},
+		{
+			name:     "Check escapes",
+			template: "{{name}} 100%",
+			want:     []string{},
+		},
+		{
+			name:     "Check unterminated parameter",
+			template: "property {name",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TemplateParams(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TemplateParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TemplateParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewT(t *testing.T) {
+	got := NewT("property {name} is not allowed", Args{"name": "baseUri"}, WithLocation("/tmp/api.raml"))
+	if got.Error() != "/tmp/api.raml:1: property baseUri is not allowed" {
+		t.Errorf("Error() = %v", got.Error())
+	}
+	if got.Template != "property {name} is not allowed" || !reflect.DeepEqual(got.Args, Args{"name": "baseUri"}) {
+		t.Errorf("NewT() template = %v, args = %v", got.Template, got.Args)
+	}
+	got.SetMessage("plain %s", "message")
+	if got.Template != "" || got.Args != nil {
+		t.Errorf("SetMessage() must reset the template, got %v, %v", got.Template, got.Args)
+	}
+}
@@ -1,5 +1,11 @@
 package stacktrace
 
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
 type TracesOpt interface {
 	Apply(o *TracesOptions)
 }
@@ -7,12 +13,21 @@ type TracesOpt interface {
 type TracesOptions struct {
 	// EnsureDuplicates ensures that duplicates are not printed
 	EnsureDuplicates bool
-	dupLocs          map[string]struct{}
+	// Localizer translates the messages, the original messages are kept if it is nil
+	Localizer Localizer
+	// Redaction redacts the sensitive data, defaults to the one set by SetRedactionPolicy
+	Redaction *RedactionPolicy
+	// InlineSuppressions is the file system of the sources whose inline suppression comments are applied
+	InlineSuppressions fs.FS
+	// Fingerprint sets the fingerprints of the traces computed with the options if not nil
+	Fingerprint *FingerprintOptions
+	dupLocs     map[string]struct{}
 }
 
 func NewTracesOptions() *TracesOptions {
 	opts := &TracesOptions{
 		EnsureDuplicates: false,
+		Redaction:        currentRedactionPolicy(),
 		dupLocs:          make(map[string]struct{}),
 	}
 	return opts
@@ -30,23 +45,118 @@ func WithEnsureDuplicates() TracesOpt {
 
 type Stack struct {
 	LinePos  *string
+	Location *Location
+	Position *Position
 	Severity *Severity
 	Message  string
+	// Text is the localized and redacted message without the Info,
+	// for the outputs writing the Info separately.
+	Text     string
 	Type     *Type
+	Code     *Code
+	Template string
+	Args     Args
+	// Info are the additional information entries with the redacted values, nil if there are none.
+	// They are included in the Message as well, but not in the Text.
+	Info map[string]string
+
+	// rawMessage is the original message without the Info if it differs from the Message,
+	// it is matched by the suppression rules.
+	rawMessage string
 }
 
 func NewStack() *Stack {
 	return &Stack{}
 }
 
+// originalMessage returns the message before the localization and the redaction, without the Info.
+func (s *Stack) originalMessage() string {
+	if s.rawMessage != "" {
+		return s.rawMessage
+	}
+	return s.Message
+}
+
+// String implements the fmt.Stringer interface.
+// It returns the type, the code, the position and the message of the stack entry.
+func (s *Stack) String() string {
+	segs := make([]string, 0, 3)
+	header := s.Type.String()
+	if s.Code != nil && *s.Code != "" {
+		header = fmt.Sprintf("%s[%s]", header, s.Code)
+	}
+	if header != "" {
+		segs = append(segs, header)
+	}
+	if s.LinePos != nil && *s.LinePos != "" {
+		segs = append(segs, *s.LinePos)
+	}
+	if s.Message != "" {
+		segs = append(segs, s.Message)
+	}
+	return strings.Join(segs, ": ")
+}
+
 type Trace struct {
 	Stack []Stack
+	// Fingerprint is the stable identity of the trace, set by WithFingerprints.
+	Fingerprint string
 }
 
 func NewTrace() *Trace {
 	return &Trace{Stack: make([]Stack, 0)}
 }
 
+// String implements the fmt.Stringer interface.
+// It returns the highest severity of the trace followed by its stack entries.
+func (t *Trace) String() string {
+	var severity *Severity
+	segs := make([]string, 0, len(t.Stack)+1)
+	for i := range t.Stack {
+		stack := &t.Stack[i]
+		if stack.Severity != nil && (severity == nil || SeverityRank(*stack.Severity) > SeverityRank(*severity)) {
+			severity = stack.Severity
+		}
+		segs = append(segs, stack.String())
+	}
+	if severity != nil {
+		segs = append([]string{severity.String()}, segs...)
+	}
+	return strings.Join(segs, ": ")
+}
+
+// newStack returns the stack entry of the StackTrace itself.
+func (st *StackTrace) newStack(opts *TracesOptions) *Stack {
+	stack := NewStack()
+	stack.LinePos = st.GetLocWithPosPtr()
+	if stack.LinePos != nil {
+		stack.Location = st.Location
+		stack.Position = st.Position
+	}
+	stack.Severity = st.Severity
+	stack.Message = st.messageWithInfo(opts.Localizer, opts.Redaction)
+	stack.Text = st.text(opts.Localizer, opts.Redaction)
+	stack.Info = st.Info.redactedMap(opts.Redaction)
+	stack.Type = st.Type
+	stack.Code = st.Code
+	stack.Template = st.Template
+	stack.Args = opts.Redaction.RedactArgs(st.Args)
+	if stack.Message != st.Message {
+		stack.rawMessage = st.Message
+	}
+	return stack
+}
+
+// RenderMessage returns the message with the additional information of the StackTrace itself
+// localized and redacted with the given options, as the messages of GetTraces.
+func (st *StackTrace) RenderMessage(opts ...TracesOpt) string {
+	o := NewTracesOptions()
+	for _, opt := range opts {
+		opt.Apply(o)
+	}
+	return st.messageWithInfo(o.Localizer, o.Redaction)
+}
+
 func (st *StackTrace) getTraces(opts *TracesOptions) []Trace {
 	traces := make([]Trace, 0)
 
@@ -59,11 +169,7 @@ func (st *StackTrace) getTraces(opts *TracesOptions) []Trace {
 	}
 
 	trace := NewTrace()
-	stack := NewStack()
-	stack.LinePos = st.GetLocWithPosPtr()
-	stack.Severity = st.Severity
-	stack.Message = st.MessageWithInfo()
-	stack.Type = st.Type
+	stack := st.newStack(opts)
 
 	if stack.LinePos != nil {
 		if _, ok := opts.dupLocs[*stack.LinePos]; ok {
@@ -81,12 +187,18 @@ func (st *StackTrace) getTraces(opts *TracesOptions) []Trace {
 			combined := Trace{Stack: make([]Stack, len(trace.Stack), len(trace.Stack)+len(wt.Stack))}
 			copy(combined.Stack, trace.Stack)
 			combined.Stack = append(combined.Stack, wt.Stack...)
+			if opts.Fingerprint != nil {
+				combined.Fingerprint = traceFingerprint(&combined, opts.Fingerprint)
+			}
 			traces = append(traces, combined)
 		}
 	} else {
 		if opts.EnsureDuplicates && stack.LinePos != nil {
 			opts.dupLocs[*stack.LinePos] = struct{}{}
 		}
+		if opts.Fingerprint != nil {
+			trace.Fingerprint = traceFingerprint(trace, opts.Fingerprint)
+		}
 		// Suppress standalone emission for locationless container nodes (no LinePos,
 		// non-empty List): they are pure grouping headers, not actionable errors.
 		if stack.LinePos != nil || len(st.List) == 0 {
@@ -102,5 +214,9 @@ func (st *StackTrace) GetTraces(opts ...TracesOpt) []Trace {
 	for _, opt := range opts {
 		opt.Apply(o)
 	}
-	return st.getTraces(o)
+	traces := st.getTraces(o)
+	if o.InlineSuppressions != nil {
+		traces = applyInlineSuppressions(o.InlineSuppressions, traces, o)
+	}
+	return traces
 }
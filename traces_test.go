@@ -45,8 +45,10 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location.raml:1"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location.raml"); return &s }(),
 							Severity: &SeverityError,
 							Message:  "error message",
+							Text:     "error message",
 							Type:     &TypeValidating,
 						},
 					},
@@ -74,14 +76,20 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location.raml:1:2"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location.raml"); return &s }(),
+							Position: &Position{1, 2},
 							Severity: &SeverityError,
 							Message:  "error message",
+							Text:     "error message",
 							Type:     &TypeValidating,
 						},
 						{
 							LinePos:  func() *string { s := "/tmp/location2.raml:3:4"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location2.raml"); return &s }(),
+							Position: &Position{3, 4},
 							Severity: &SeverityCritical,
 							Message:  "error message 2",
+							Text:     "error message 2",
 							Type:     &TypeParsing,
 						},
 					},
@@ -130,14 +138,20 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location.raml:1:2"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location.raml"); return &s }(),
+							Position: &Position{1, 2},
 							Severity: &SeverityError,
 							Message:  "error message",
+							Text:     "error message",
 							Type:     &TypeValidating,
 						},
 						{
 							LinePos:  func() *string { s := "/tmp/location2.raml:3:4"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location2.raml"); return &s }(),
+							Position: &Position{3, 4},
 							Severity: &SeverityCritical,
 							Message:  "error message 2",
+							Text:     "error message 2",
 							Type:     &TypeParsing,
 						},
 					},
@@ -174,8 +188,11 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location.raml:1:2"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location.raml"); return &s }(),
+							Position: &Position{1, 2},
 							Severity: &SeverityError,
 							Message:  "error message",
+							Text:     "error message",
 							Type:     &TypeValidating,
 						},
 					},
@@ -184,8 +201,11 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location2.raml:3:4"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location2.raml"); return &s }(),
+							Position: &Position{3, 4},
 							Severity: &SeverityCritical,
 							Message:  "error message 2",
+							Text:     "error message 2",
 							Type:     &TypeParsing,
 						},
 					},
@@ -194,8 +214,11 @@ func TestStackTrace_GetTraces(t *testing.T) {
 					Stack: []Stack{
 						{
 							LinePos:  func() *string { s := "/tmp/location3.raml:5:6"; return &s }(),
+							Location: func() *Location { s := Location("/tmp/location3.raml"); return &s }(),
+							Position: &Position{5, 6},
 							Severity: &SeverityCritical,
 							Message:  "error message 3",
+							Text:     "error message 3",
 							Type:     &TypeParsing,
 						},
 					},